@@ -0,0 +1,75 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"time"
+)
+
+// Empty refreshes in a row before the refresh interval starts growing
+const idleRefreshesBeforeBackoff = 3
+
+// Shortest interval between refreshes, so a zero REFRESH can't spin the
+// refresh loop, such as while every fetch fails straight away
+const minRefreshInterval = 100 * time.Millisecond
+
+// Slows refreshes while the mempool stays empty, doubling the interval up
+// to a maximum, and snaps back to the base interval once transactions show
+// up again
+type AdaptiveInterval struct {
+	base       time.Duration
+	max        time.Duration
+	emptyCount int
+	current    time.Duration
+}
+
+// Creates a policy growing from base up to max. A max no greater than base
+// keeps the interval fixed. Base is raised to minRefreshInterval if lower
+func NewAdaptiveInterval(base, max time.Duration) *AdaptiveInterval {
+	if base < minRefreshInterval {
+		base = minRefreshInterval
+	}
+	return &AdaptiveInterval{
+		base:    base,
+		max:     max,
+		current: base,
+	}
+}
+
+// Changes the interval refreshes return to, such as when it's adjusted
+// while running. Base is raised to minRefreshInterval if lower
+func (a *AdaptiveInterval) SetBase(base time.Duration) {
+	base = max(base, minRefreshInterval)
+	if a.current == a.base || a.current < base {
+		a.current = base
+	}
+	a.base = base
+}
+
+// Returns the interval before the next refresh, given the number of
+// transactions in the last one. A negative count means the refresh failed,
+// which resets the interval
+func (a *AdaptiveInterval) Next(txCount int) time.Duration {
+	if txCount != 0 || a.max <= a.base {
+		a.emptyCount = 0
+		a.current = a.base
+		return a.current
+	}
+	a.emptyCount++
+	if a.emptyCount > idleRefreshesBeforeBackoff {
+		a.current = min(a.current*2, a.max)
+	}
+	return a.current
+}
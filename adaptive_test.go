@@ -0,0 +1,108 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdaptiveIntervalGrowsWhileEmpty(t *testing.T) {
+	a := NewAdaptiveInterval(time.Second, 10*time.Second)
+	var got []time.Duration
+	for range idleRefreshesBeforeBackoff + 5 {
+		got = append(got, a.Next(0))
+	}
+	want := []time.Duration{
+		time.Second,
+		time.Second,
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		10 * time.Second,
+		10 * time.Second,
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("refresh %d: got %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestAdaptiveIntervalResets(t *testing.T) {
+	tests := []struct {
+		name    string
+		txCount int
+	}{
+		{"activity", 3},
+		{"failure", -1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := NewAdaptiveInterval(time.Second, time.Minute)
+			for range idleRefreshesBeforeBackoff + 3 {
+				a.Next(0)
+			}
+			if got := a.Next(test.txCount); got != time.Second {
+				t.Errorf("got %s, want the base interval", got)
+			}
+			if got := a.Next(0); got != time.Second {
+				t.Errorf("emptiness not counted afresh, got %s", got)
+			}
+		})
+	}
+}
+
+func TestAdaptiveIntervalDisabled(t *testing.T) {
+	a := NewAdaptiveInterval(time.Second, 0)
+	for range idleRefreshesBeforeBackoff + 3 {
+		if got := a.Next(0); got != time.Second {
+			t.Fatalf("got %s, want a fixed interval", got)
+		}
+	}
+}
+
+func TestAdaptiveIntervalZeroBase(t *testing.T) {
+	a := NewAdaptiveInterval(0, time.Second)
+	for _, txCount := range []int{-1, -1, 0, 0, 0, 0, 0} {
+		if got := a.Next(txCount); got < minRefreshInterval {
+			t.Fatalf("got %s, below the minimum", got)
+		}
+	}
+	if got := a.Next(0); got <= minRefreshInterval {
+		t.Errorf("zero base never backed off, got %s", got)
+	}
+}
+
+func TestAdaptiveIntervalSetBase(t *testing.T) {
+	a := NewAdaptiveInterval(2*time.Second, time.Minute)
+	a.SetBase(500 * time.Millisecond)
+	if got := a.Next(3); got != 500*time.Millisecond {
+		t.Errorf("got %s, want the new base", got)
+	}
+	for range idleRefreshesBeforeBackoff + 3 {
+		a.Next(0)
+	}
+	// A longer base applies right away, even while backed off
+	a.SetBase(30 * time.Second)
+	if got := a.Next(0); got < 30*time.Second {
+		t.Errorf("got %s, want at least the new base", got)
+	}
+	a.SetBase(0)
+	if got := a.Next(1); got != minRefreshInterval {
+		t.Errorf("got %s, want %s", got, minRefreshInterval)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var txAges = NewAgeTracker()
+
+// Remembers when each transaction hash was first observed in the mempool
+type AgeTracker struct {
+	sync.Mutex
+	firstSeen map[string]time.Time
+	// Hashes first observed by this process
+	seen int
+}
+
+func NewAgeTracker() *AgeTracker {
+	return &AgeTracker{
+		firstSeen: make(map[string]time.Time),
+	}
+}
+
+// Records now as the first-seen time of hashes we haven't seen before. When
+// complete, hashes is the whole mempool and any others are forgotten; after
+// a partial drain we can't tell which are gone, so nothing is forgotten
+func (a *AgeTracker) Observe(hashes []string, now time.Time, complete bool) {
+	a.Lock()
+	defer a.Unlock()
+	present := make(map[string]bool, len(hashes))
+	for _, hash := range hashes {
+		if _, ok := a.firstSeen[hash]; !ok {
+			a.firstSeen[hash] = now
+			a.seen++
+		}
+		present[hash] = true
+	}
+	if !complete {
+		return
+	}
+	for hash := range a.firstSeen {
+		if !present[hash] {
+			delete(a.firstSeen, hash)
+		}
+	}
+}
+
+// Returns how many distinct transactions were first observed by this
+// process. A transaction which leaves the mempool and returns is counted
+// again
+func (a *AgeTracker) Seen() int {
+	a.Lock()
+	defer a.Unlock()
+	return a.seen
+}
+
+// Fills in FirstSeen for each record from the observed times, using now for
+// any which weren't observed
+func (a *AgeTracker) Fill(records []TxRecord, now time.Time) []TxRecord {
+	a.Lock()
+	defer a.Unlock()
+	for i, record := range records {
+		firstSeen, ok := a.firstSeen[record.Hash]
+		if !ok {
+			firstSeen = now
+		}
+		records[i].FirstSeen = firstSeen
+	}
+	return records
+}
+
+// Writes the tracked hashes and their first-seen times to path as JSON
+func (a *AgeTracker) Save(path string) error {
+	a.Lock()
+	data, err := json.Marshal(a.firstSeen)
+	a.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Replaces the tracked hashes with those saved to path by a previous run. A
+// missing file isn't an error, as there is nothing to resume
+func (a *AgeTracker) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	firstSeen := make(map[string]time.Time)
+	if err := json.Unmarshal(data, &firstSeen); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	a.Lock()
+	defer a.Unlock()
+	a.firstSeen = firstSeen
+	return nil
+}
+
+// A range of mempool residence times and how many transactions fall in it
+type bucket struct {
+	label string
+	// Upper bound (exclusive), or zero for no bound
+	max   time.Duration
+	count int
+}
+
+// Counts how long transactions have been in the mempool
+func residenceBuckets(ages []time.Duration) []bucket {
+	buckets := []bucket{
+		{label: "<5s", max: 5 * time.Second},
+		{label: "5-30s", max: 30 * time.Second},
+		{label: "30s-2m", max: 2 * time.Minute},
+		{label: ">2m"},
+	}
+	for _, age := range ages {
+		for i := range buckets {
+			if buckets[i].max == 0 || age < buckets[i].max {
+				buckets[i].count++
+				break
+			}
+		}
+	}
+	return buckets
+}
+
+func FormatResidence(records []TxRecord, now time.Time) string {
+	ages := make([]time.Duration, 0, len(records))
+	for _, record := range records {
+		ages = append(ages, now.Sub(record.FirstSeen))
+	}
+	var sb strings.Builder
+	sb.WriteString(" [white]Residence:")
+	for _, b := range residenceBuckets(ages) {
+		sb.WriteString(
+			fmt.Sprintf(" %s [blue]%d[white]", b.label, b.count),
+		)
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
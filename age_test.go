@@ -0,0 +1,128 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAgeTrackerObserve(t *testing.T) {
+	start := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	later := start.Add(time.Minute)
+	tests := []struct {
+		name     string
+		second   []string
+		complete bool
+		want     map[string]time.Time
+	}{
+		{
+			name:     "complete drain forgets missing hashes",
+			second:   []string{"b", "c"},
+			complete: true,
+			want:     map[string]time.Time{"b": start, "c": later},
+		},
+		{
+			name:     "partial drain keeps missing hashes",
+			second:   []string{"b", "c"},
+			complete: false,
+			want: map[string]time.Time{
+				"a": start,
+				"b": start,
+				"c": later,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := NewAgeTracker()
+			a.Observe([]string{"a", "b"}, start, true)
+			a.Observe(test.second, later, test.complete)
+			if len(a.firstSeen) != len(test.want) {
+				t.Fatalf("got %v, want %v", a.firstSeen, test.want)
+			}
+			for hash, want := range test.want {
+				if got := a.firstSeen[hash]; !got.Equal(want) {
+					t.Errorf("%s: got %s, want %s", hash, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAgeTrackerFill(t *testing.T) {
+	start := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	now := start.Add(time.Minute)
+	a := NewAgeTracker()
+	a.Observe([]string{"a"}, start, true)
+	records := a.Fill([]TxRecord{{Hash: "a"}, {Hash: "unseen"}}, now)
+	if !records[0].FirstSeen.Equal(start) {
+		t.Errorf("observed hash: got %s, want %s", records[0].FirstSeen, start)
+	}
+	if !records[1].FirstSeen.Equal(now) {
+		t.Errorf("unseen hash: got %s, want %s", records[1].FirstSeen, now)
+	}
+}
+
+func TestAgeTrackerSaveLoad(t *testing.T) {
+	start := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "seen.json")
+	saved := NewAgeTracker()
+	saved.Observe([]string{"a", "b"}, start, true)
+	if err := saved.Save(path); err != nil {
+		t.Fatalf("save: %s", err)
+	}
+	loaded := NewAgeTracker()
+	if err := loaded.Load(path); err != nil {
+		t.Fatalf("load: %s", err)
+	}
+	for _, hash := range []string{"a", "b"} {
+		if got := loaded.firstSeen[hash]; !got.Equal(start) {
+			t.Errorf("%s: got %s, want %s", hash, got, start)
+		}
+	}
+}
+
+func TestAgeTrackerLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	if err := NewAgeTracker().Load(filepath.Join(dir, "missing")); err != nil {
+		t.Errorf("missing file: unexpected error %s", err)
+	}
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewAgeTracker().Load(corrupt); err == nil {
+		t.Error("corrupt file: expected an error")
+	}
+}
+
+func TestResidenceBuckets(t *testing.T) {
+	ages := []time.Duration{
+		time.Second,
+		10 * time.Second,
+		time.Minute,
+		5 * time.Minute,
+		2 * time.Minute,
+	}
+	want := []int{1, 1, 1, 2}
+	for i, b := range residenceBuckets(ages) {
+		if b.count != want[i] {
+			t.Errorf("%s: got %d, want %d", b.label, b.count, want[i])
+		}
+	}
+}
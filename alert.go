@@ -0,0 +1,160 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// How long a large transaction alert stays in the footer
+const alertFlashDuration = 10 * time.Second
+
+// Longest a webhook request may take
+const alertWebhookTimeout = 5 * time.Second
+
+var largeTxAlerts = NewLargeTxAlerter(0)
+
+// Picks out transactions larger than a threshold, alerting once for each
+type LargeTxAlerter struct {
+	sync.Mutex
+	threshold int
+	alerted   map[string]struct{}
+	flash     string
+	flashTime time.Time
+}
+
+// Creates an alerter for transactions larger than threshold bytes. A zero
+// threshold disables alerting
+func NewLargeTxAlerter(threshold int) *LargeTxAlerter {
+	return &LargeTxAlerter{
+		threshold: threshold,
+		alerted:   make(map[string]struct{}),
+	}
+}
+
+// Returns the transactions in records over the threshold which haven't
+// been alerted for yet. Hashes are forgotten once they leave the mempool,
+// so the alerted set doesn't grow for the whole run
+func (a *LargeTxAlerter) Observe(records []TxRecord) []TxRecord {
+	if a.threshold <= 0 {
+		return nil
+	}
+	a.Lock()
+	defer a.Unlock()
+	current := make(map[string]struct{}, len(a.alerted))
+	var alerts []TxRecord
+	for _, record := range records {
+		if record.Size <= a.threshold {
+			continue
+		}
+		current[record.Hash] = struct{}{}
+		if _, ok := a.alerted[record.Hash]; !ok {
+			alerts = append(alerts, record)
+		}
+	}
+	a.alerted = current
+	return alerts
+}
+
+// Shows alerts in the footer for a while
+func (a *LargeTxAlerter) SetFlash(alerts []TxRecord, now time.Time) {
+	a.Lock()
+	defer a.Unlock()
+	a.flash = formatLargeTxAlert(alerts)
+	a.flashTime = now
+}
+
+// The latest alert while it's recent enough to show, or nothing
+func (a *LargeTxAlerter) Flash(now time.Time) string {
+	a.Lock()
+	defer a.Unlock()
+	if a.flash == "" || now.Sub(a.flashTime) >= alertFlashDuration {
+		return ""
+	}
+	return a.flash
+}
+
+// Describes newly large transactions in a few words for the footer
+func formatLargeTxAlert(alerts []TxRecord) string {
+	largest := alerts[0]
+	for _, record := range alerts[1:] {
+		if record.Size > largest.Size {
+			largest = record
+		}
+	}
+	text := fmt.Sprintf("large tx %.8s (%d bytes)", largest.Hash, largest.Size)
+	if len(alerts) > 1 {
+		text += fmt.Sprintf(" and %d more", len(alerts)-1)
+	}
+	return text
+}
+
+// Body of a large transaction webhook request
+type largeTxWebhook struct {
+	Threshold    int           `json:"threshold"`
+	Transactions []apiTxRecord `json:"transactions"`
+}
+
+// Posts the alerts to url as JSON, logging any failure
+func postLargeTxWebhook(url string, threshold int, alerts []TxRecord) {
+	body, err := json.Marshal(largeTxWebhook{
+		Threshold:    threshold,
+		Transactions: newAPITxRecords(alerts),
+	})
+	if err != nil {
+		log.Printf("failed to encode large transaction alert: %s", err)
+		return
+	}
+	client := http.Client{Timeout: alertWebhookTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Printf("failed to send large transaction alert: %s", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		log.Printf(
+			"large transaction alert webhook returned %s",
+			resp.Status,
+		)
+	}
+}
+
+// Alerts for the transactions in snapshot which just went over
+// LARGE_TX_ALERT, posting them to LARGE_TX_WEBHOOK when it's set
+func alertLargeTxs(cfg *Config, snapshot Snapshot) {
+	alerts := largeTxAlerts.Observe(snapshot.Records)
+	if len(alerts) == 0 {
+		return
+	}
+	if cfg.App.RedactHashes {
+		alerts = redactRecords(alerts, redactKey)
+	}
+	largeTxAlerts.SetFlash(alerts, snapshot.Time)
+	if cfg.App.LargeTxWebhook == "" {
+		return
+	}
+	go postLargeTxWebhook(
+		cfg.App.LargeTxWebhook,
+		int(cfg.App.LargeTxAlert),
+		alerts,
+	)
+}
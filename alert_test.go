@@ -0,0 +1,139 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func alertHashes(records []TxRecord) []string {
+	var hashes []string
+	for _, record := range records {
+		hashes = append(hashes, record.Hash)
+	}
+	return hashes
+}
+
+func TestLargeTxAlerterObserve(t *testing.T) {
+	small := TxRecord{Hash: "small", Size: 1000}
+	edge := TxRecord{Hash: "edge", Size: 5000}
+	large := TxRecord{Hash: "large", Size: 8000}
+	larger := TxRecord{Hash: "larger", Size: 12000}
+	tests := []struct {
+		name      string
+		threshold int
+		refreshes [][]TxRecord
+		want      [][]string
+	}{
+		{
+			"disabled",
+			0,
+			[][]TxRecord{{small, large}},
+			[][]string{nil},
+		},
+		{
+			"over the threshold only",
+			5000,
+			[][]TxRecord{{small, edge, large}},
+			[][]string{{"large"}},
+		},
+		{
+			"once per hash",
+			5000,
+			[][]TxRecord{{large}, {large, small}, {large}},
+			[][]string{{"large"}, nil, nil},
+		},
+		{
+			"new hashes while others stay",
+			5000,
+			[][]TxRecord{{large}, {large, larger}},
+			[][]string{{"large"}, {"larger"}},
+		},
+		{
+			"again after leaving the mempool",
+			5000,
+			[][]TxRecord{{large}, {small}, {large}},
+			[][]string{{"large"}, nil, {"large"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			alerter := NewLargeTxAlerter(test.threshold)
+			for i, records := range test.refreshes {
+				got := alertHashes(alerter.Observe(records))
+				if !slices.Equal(got, test.want[i]) {
+					t.Errorf(
+						"refresh %d: got %v, want %v",
+						i,
+						got,
+						test.want[i],
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestLargeTxAlerterFlash(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	alerter := NewLargeTxAlerter(5000)
+	if got := alerter.Flash(now); got != "" {
+		t.Errorf("got %q before any alert", got)
+	}
+	alerter.SetFlash(
+		[]TxRecord{
+			{Hash: "0123456789abcdef", Size: 8000},
+			{Hash: "fedcba9876543210", Size: 12000},
+		},
+		now,
+	)
+	want := "large tx fedcba98 (12000 bytes) and 1 more"
+	if got := alerter.Flash(now.Add(time.Second)); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := alerter.Flash(now.Add(alertFlashDuration)); got != "" {
+		t.Errorf("got %q after the flash expired", got)
+	}
+}
+
+func TestPostLargeTxWebhook(t *testing.T) {
+	received := make(chan largeTxWebhook, 1)
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body largeTxWebhook
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("failed to decode body: %s", err)
+			}
+			received <- body
+		}),
+	)
+	defer server.Close()
+	postLargeTxWebhook(
+		server.URL,
+		5000,
+		[]TxRecord{{Hash: "large", Size: 8000}},
+	)
+	body := <-received
+	if body.Threshold != 5000 || len(body.Transactions) != 1 ||
+		body.Transactions[0].Hash != "large" ||
+		body.Transactions[0].Size != 8000 {
+		t.Errorf("got %+v", body)
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// JSON form of a snapshot served by the HTTP API
+type apiMempool struct {
+	Time         time.Time     `json:"time"`
+	Hash         string        `json:"hash"`
+	Capacity     uint32        `json:"capacity"`
+	Size         uint32        `json:"size"`
+	NumberOfTxs  uint32        `json:"numberOfTxs"`
+	Transactions []apiTxRecord `json:"transactions"`
+}
+
+type apiTxRecord struct {
+	Hash      string    `json:"hash"`
+	Size      int       `json:"size"`
+	Icon      string    `json:"icon,omitempty"`
+	Label     string    `json:"label,omitempty"`
+	Era       string    `json:"era,omitempty"`
+	Fee       uint64    `json:"fee"`
+	FirstSeen time.Time `json:"firstSeen"`
+	Conflict  bool      `json:"conflict,omitempty"`
+}
+
+func newAPIMempool(cfg *Config, snapshot Snapshot) apiMempool {
+	records := snapshot.Records
+	if cfg.App.RedactHashes {
+		records = redactRecords(records, redactKey)
+	}
+	return apiMempool{
+		Time:         snapshot.Time,
+		Hash:         snapshot.Hash,
+		Capacity:     snapshot.Sizes.Capacity,
+		Size:         snapshot.Sizes.Size,
+		NumberOfTxs:  snapshot.Sizes.NumberOfTxs,
+		Transactions: newAPITxRecords(records),
+	}
+}
+
+func newAPITxRecords(records []TxRecord) []apiTxRecord {
+	txs := make([]apiTxRecord, 0, len(records))
+	for _, record := range records {
+		txs = append(txs, apiTxRecord{
+			Hash:      record.Hash,
+			Size:      record.Size,
+			Icon:      record.Icon,
+			Label:     record.Label,
+			Era:       record.Era,
+			Fee:       record.Fee,
+			FirstSeen: record.FirstSeen,
+			Conflict:  record.Conflict,
+		})
+	}
+	return txs
+}
+
+// Returns the ETag for a snapshot, from its transactions and sizes. The read
+// and first seen times are left out, so it only changes with the mempool
+func mempoolETag(snapshot Snapshot) string {
+	sum := sha256.Sum256(
+		[]byte(
+			fmt.Sprintf(
+				"%s:%d:%d:%d",
+				snapshot.Hash,
+				snapshot.Sizes.Capacity,
+				snapshot.Sizes.Size,
+				snapshot.Sizes.NumberOfTxs,
+			),
+		),
+	)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// Serves the latest snapshot as JSON. An ETag of the mempool contents is
+// sent so polling clients can send If-None-Match and get a 304 until the
+// mempool changes
+func mempoolHandler(
+	cfg *Config,
+	getSnapshot func() Snapshot,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		snapshot := getSnapshot()
+		if snapshot.Time.IsZero() {
+			http.Error(
+				w,
+				"no snapshot available yet",
+				http.StatusServiceUnavailable,
+			)
+			return
+		}
+		etag := mempoolETag(snapshot)
+		w.Header().Set("ETag", etag)
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		body, err := json.Marshal(newAPIMempool(cfg, snapshot))
+		if err != nil {
+			log.Printf("failed to encode /mempool response: %s", err)
+			http.Error(w, "encoding failed", http.StatusInternalServerError)
+			return
+		}
+		body = append(body, '\n')
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(body); err != nil {
+			log.Printf("failed to write /mempool response: %s", err)
+		}
+	}
+}
+
+// Reports whether an If-None-Match header, a comma separated list of entity
+// tags or "*", matches etag. If-None-Match uses the weak comparison, so a W/
+// prefix on either side is ignored
+func etagMatches(header string, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// Per-label statistics served by the HTTP API
+type apiStats struct {
+	Window string                `json:"window"`
+	Labels map[string]LabelStats `json:"labels"`
+}
+
+// Serves the transactions seen per label over the stats window
+func statsHandler(
+	window time.Duration,
+	stats *WindowedStats,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(
+			apiStats{
+				Window: window.String(),
+				Labels: stats.Totals(time.Now()),
+			},
+		)
+		if err != nil {
+			log.Printf("failed to write /stats response: %s", err)
+		}
+	}
+}
+
+// Reports 200 while check passes and 503 otherwise
+func probeHandler(check func() bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !check() {
+			http.Error(w, "not ok", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok\n"))
+	}
+}
+
+// Starts the HTTP API in the background when an address is configured
+func startAPI(cfg *Config) {
+	if cfg.App.APIAddress == "" {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/mempool", mempoolHandler(cfg, getLastSnapshot))
+	mux.Handle(
+		"/mempool/stream",
+		streamHandler(cfg, getLastSnapshot, snapshotStream),
+	)
+	mux.Handle(
+		"/stats",
+		statsHandler(
+			time.Second*time.Duration(cfg.App.StatsWindow),
+			labelStats,
+		),
+	)
+	// Ready once a snapshot has been read, healthy while reading works
+	mux.Handle("/readyz", probeHandler(snapshotReady.Load))
+	mux.Handle("/healthz", probeHandler(snapshotHealthy.Load))
+	go func() {
+		if err := http.ListenAndServe(cfg.App.APIAddress, mux); err != nil {
+			log.Printf("API server failed: %s", err)
+		}
+	}()
+}
@@ -0,0 +1,135 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func testAPISnapshot() Snapshot {
+	start := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	records := []TxRecord{
+		{Hash: "abc", Size: 300, Fee: 170000, FirstSeen: start},
+	}
+	return Snapshot{
+		Time:    start,
+		Sizes:   MempoolSizes{Capacity: 1000, Size: 300, NumberOfTxs: 1},
+		Records: records,
+		Hash:    snapshotHash(records),
+	}
+}
+
+func getMempool(
+	t *testing.T,
+	snapshot Snapshot,
+	ifNoneMatch string,
+) *httptest.ResponseRecorder {
+	t.Helper()
+	handler := mempoolHandler(
+		&Config{},
+		func() Snapshot { return snapshot },
+	)
+	req := httptest.NewRequest(http.MethodGet, "/mempool", nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func TestMempoolHandlerNoSnapshot(t *testing.T) {
+	rec := getMempool(t, Snapshot{}, "")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("got %d, want 503", rec.Code)
+	}
+}
+
+func TestMempoolHandlerBody(t *testing.T) {
+	rec := getMempool(t, testAPISnapshot(), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d, want 200", rec.Code)
+	}
+	var body apiMempool
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding body: %s", err)
+	}
+	if body.NumberOfTxs != 1 || len(body.Transactions) != 1 ||
+		body.Transactions[0].Hash != "abc" {
+		t.Errorf("got %+v", body)
+	}
+}
+
+func TestMempoolHandlerETag(t *testing.T) {
+	snapshot := testAPISnapshot()
+	etag := getMempool(t, snapshot, "").Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("no ETag sent")
+	}
+	resized := testAPISnapshot()
+	resized.Sizes.Size = 400
+	// The same transactions read again later, first seen in between
+	later := testAPISnapshot()
+	later.Time = later.Time.Add(time.Minute)
+	later.Records[0].FirstSeen = later.Records[0].FirstSeen.Add(time.Second)
+	changed := testAPISnapshot()
+	changed.Records = []TxRecord{{Hash: "def", Size: 300}}
+	changed.Hash = snapshotHash(changed.Records)
+	tests := []struct {
+		name        string
+		snapshot    Snapshot
+		ifNoneMatch string
+		want        int
+	}{
+		{"no header", snapshot, "", http.StatusOK},
+		{"exact match", snapshot, etag, http.StatusNotModified},
+		{"weak match", snapshot, "W/" + etag, http.StatusNotModified},
+		{"in list", snapshot, `"other", ` + etag, http.StatusNotModified},
+		{"wildcard", snapshot, "*", http.StatusNotModified},
+		{"other tag", snapshot, `"other"`, http.StatusOK},
+		{"sizes changed", resized, etag, http.StatusOK},
+		{"read again later", later, etag, http.StatusNotModified},
+		{"transactions changed", changed, etag, http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := getMempool(t, test.snapshot, test.ifNoneMatch)
+			if rec.Code != test.want {
+				t.Errorf("got %d, want %d", rec.Code, test.want)
+			}
+		})
+	}
+}
+
+func TestProbeHandler(t *testing.T) {
+	for _, ok := range []bool{true, false} {
+		rec := httptest.NewRecorder()
+		probeHandler(func() bool { return ok })(
+			rec,
+			httptest.NewRequest(http.MethodGet, "/healthz", nil),
+		)
+		want := http.StatusOK
+		if !ok {
+			want = http.StatusServiceUnavailable
+		}
+		if rec.Code != want {
+			t.Errorf("check %t: got %d, want %d", ok, rec.Code, want)
+		}
+	}
+}
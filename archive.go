@@ -0,0 +1,148 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Archive file names are the prefix, the UTC time of the snapshot in this
+// layout and the suffix, so sorting them by name sorts them by age
+const (
+	archivePrefix     = "txtop-"
+	archiveTimeLayout = "20060102T150405Z"
+	archiveSuffix     = ".json"
+)
+
+// Writes a snapshot to a new file in a directory every interval, keeping
+// only the most recent files
+type SnapshotArchiver struct {
+	sync.Mutex
+	dir      string
+	interval time.Duration
+	keep     int
+	last     time.Time
+}
+
+// Set up in main when ARCHIVE_INTERVAL is configured
+var snapshotArchiver *SnapshotArchiver
+
+// Creates an archiver writing to dir, creating it if needed. A keep of zero
+// keeps every file
+func NewSnapshotArchiver(
+	dir string,
+	interval time.Duration,
+	keep int,
+) (*SnapshotArchiver, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &SnapshotArchiver{dir: dir, interval: interval, keep: keep}, nil
+}
+
+// Returns whether a snapshot taken at now should be archived, which is the
+// case for the first one and then once an interval has passed
+func (a *SnapshotArchiver) Due(now time.Time) bool {
+	a.Lock()
+	defer a.Unlock()
+	return a.last.IsZero() || now.Sub(a.last) >= a.interval
+}
+
+// Writes data as the archive for a snapshot taken at now, then prunes the
+// oldest archives. Returns the path written
+func (a *SnapshotArchiver) Archive(data []byte, now time.Time) (string, error) {
+	a.Lock()
+	defer a.Unlock()
+	// A failed archive is retried after the interval, not every refresh
+	a.last = now
+	name := archivePrefix + now.UTC().Format(archiveTimeLayout) + archiveSuffix
+	path := filepath.Join(a.dir, name)
+	// Write to a temporary file first, so a reader never sees a partial
+	// archive
+	tmp, err := os.CreateTemp(a.dir, "."+name+".*")
+	if err != nil {
+		return "", err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := pruneArchives(a.dir, a.keep); err != nil {
+		return path, fmt.Errorf("failed to prune archives: %w", err)
+	}
+	return path, nil
+}
+
+// Removes all but the keep most recent archives in dir, leaving any other
+// files alone. A keep of zero keeps every archive
+func pruneArchives(dir string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	var archives []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() &&
+			strings.HasPrefix(name, archivePrefix) &&
+			strings.HasSuffix(name, archiveSuffix) {
+			archives = append(archives, name)
+		}
+	}
+	if len(archives) <= keep {
+		return nil
+	}
+	slices.Sort(archives)
+	for _, name := range archives[:len(archives)-keep] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Archives snapshot, in the same form as the /mempool endpoint, when
+// ARCHIVE_INTERVAL has passed since the last archive
+func archiveSnapshot(cfg *Config, snapshot Snapshot) {
+	if snapshotArchiver == nil || !snapshotArchiver.Due(snapshot.Time) {
+		return
+	}
+	data, err := json.Marshal(newAPIMempool(cfg, snapshot))
+	if err != nil {
+		log.Printf("failed to encode snapshot for archive: %s", err)
+		return
+	}
+	if _, err := snapshotArchiver.Archive(data, snapshot.Time); err != nil {
+		log.Printf("failed to archive snapshot: %s", err)
+	}
+}
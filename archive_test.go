@@ -0,0 +1,187 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Lists the names of the files in dir
+func dirNames(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestSnapshotArchiverRotation(t *testing.T) {
+	tests := []struct {
+		name     string
+		keep     int
+		archives int
+		want     []string
+	}{
+		{
+			"fewer than keep",
+			3,
+			2,
+			[]string{
+				"txtop-20231114T221320Z.json",
+				"txtop-20231114T222320Z.json",
+			},
+		},
+		{
+			"oldest pruned",
+			3,
+			5,
+			[]string{
+				"txtop-20231114T223320Z.json",
+				"txtop-20231114T224320Z.json",
+				"txtop-20231114T225320Z.json",
+			},
+		},
+		{
+			"keep one",
+			1,
+			4,
+			[]string{"txtop-20231114T224320Z.json"},
+		},
+		{
+			"keep all",
+			0,
+			4,
+			[]string{
+				"txtop-20231114T221320Z.json",
+				"txtop-20231114T222320Z.json",
+				"txtop-20231114T223320Z.json",
+				"txtop-20231114T224320Z.json",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "archive")
+			archiver, err := NewSnapshotArchiver(
+				dir,
+				10*time.Minute,
+				test.keep,
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			start := time.Unix(1700000000, 0)
+			for i := range test.archives {
+				now := start.Add(time.Duration(i) * 10 * time.Minute)
+				if _, err := archiver.Archive([]byte("{}"), now); err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			}
+			if got := dirNames(t, dir); !slices.Equal(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPruneArchivesLeavesOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{
+		"notes.txt",
+		"txtop-20231114T221320Z.json",
+		"txtop-20231114T222320Z.json",
+		"txtop-20231114T223320Z.json",
+	}
+	for _, name := range names {
+		err := os.WriteFile(filepath.Join(dir, name), nil, 0o644)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if err := pruneArchives(dir, 1); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []string{"notes.txt", "txtop-20231114T223320Z.json"}
+	if got := dirNames(t, dir); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSnapshotArchiverContents(t *testing.T) {
+	archiver, err := NewSnapshotArchiver(t.TempDir(), time.Minute, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	path, err := archiver.Archive([]byte(`{"size":42}`), time.Unix(0, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(data) != `{"size":42}` {
+		t.Errorf("got %q", data)
+	}
+}
+
+func TestSnapshotArchiverDue(t *testing.T) {
+	archiver, err := NewSnapshotArchiver(t.TempDir(), 10*time.Minute, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	start := time.Unix(1700000000, 0)
+	if !archiver.Due(start) {
+		t.Error("first snapshot not due")
+	}
+	if _, err := archiver.Archive([]byte("{}"), start); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tests := []struct {
+		after time.Duration
+		want  bool
+	}{
+		{time.Minute, false},
+		{10*time.Minute - time.Second, false},
+		{10 * time.Minute, true},
+		{time.Hour, true},
+	}
+	for _, test := range tests {
+		if got := archiver.Due(start.Add(test.after)); got != test.want {
+			t.Errorf("after %s: got %t, want %t", test.after, got, test.want)
+		}
+	}
+}
+
+func TestConfigValidateArchiveDir(t *testing.T) {
+	cfg := testConfig()
+	cfg.App.ArchiveInterval = 5
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected an error without ARCHIVE_DIR")
+	}
+	cfg.App.ArchiveDir = t.TempDir()
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+type breakerState int
+
+const (
+	// Fetching normally
+	breakerClosed breakerState = iota
+	// Too many failures, waiting for the backoff to pass
+	breakerOpen
+	// Backoff passed, allowing one attempt to decide whether to close
+	breakerHalfOpen
+)
+
+// Stops fetching from an unreachable node for a while after repeated
+// failures, rather than retrying every refresh forever
+type CircuitBreaker struct {
+	sync.Mutex
+	threshold   int
+	backoff     time.Duration
+	failures    int
+	state       breakerState
+	nextAttempt time.Time
+}
+
+var connBreaker = NewCircuitBreaker(0, 0)
+
+// Creates a breaker which opens after threshold consecutive failures and
+// waits backoff before trying again. A zero threshold or backoff disables it
+func NewCircuitBreaker(threshold int, backoff time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold: threshold,
+		backoff:   backoff,
+	}
+}
+
+// Reports whether an attempt may be made now, and if not, when the next
+// attempt will be allowed
+func (b *CircuitBreaker) Allow(now time.Time) (bool, time.Time) {
+	b.Lock()
+	defer b.Unlock()
+	switch b.state {
+	case breakerOpen:
+		if now.Before(b.nextAttempt) {
+			return false, b.nextAttempt
+		}
+		b.state = breakerHalfOpen
+		return true, time.Time{}
+	default:
+		return true, time.Time{}
+	}
+}
+
+// Records a failed attempt, opening the breaker once failures reach the
+// threshold or when the attempt after a backoff fails
+func (b *CircuitBreaker) Failure(now time.Time) {
+	b.Lock()
+	defer b.Unlock()
+	if b.threshold <= 0 || b.backoff <= 0 {
+		return
+	}
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.nextAttempt = now.Add(b.backoff)
+	}
+}
+
+// Records a successful attempt, closing the breaker
+func (b *CircuitBreaker) Success() {
+	b.Lock()
+	defer b.Unlock()
+	b.failures = 0
+	b.state = breakerClosed
+}
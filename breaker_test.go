@@ -0,0 +1,68 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	start := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	b := NewCircuitBreaker(2, time.Minute)
+	b.Failure(start)
+	if ok, _ := b.Allow(start); !ok {
+		t.Fatal("opened before reaching the threshold")
+	}
+	b.Failure(start)
+	ok, next := b.Allow(start.Add(time.Second))
+	if ok || !next.Equal(start.Add(time.Minute)) {
+		t.Fatalf("got (%t, %s), want closed until %s", ok, next, start)
+	}
+	// After the backoff a single attempt is allowed, and failing it opens
+	// the breaker again straight away
+	retry := start.Add(time.Minute)
+	if ok, _ := b.Allow(retry); !ok {
+		t.Fatal("no attempt allowed after the backoff")
+	}
+	b.Failure(retry)
+	if ok, _ := b.Allow(retry); ok {
+		t.Fatal("failed half-open attempt didn't reopen")
+	}
+	later := retry.Add(time.Minute)
+	if ok, _ := b.Allow(later); !ok {
+		t.Fatal("no attempt allowed after the second backoff")
+	}
+	b.Success()
+	b.Failure(later)
+	if ok, _ := b.Allow(later); !ok {
+		t.Error("success didn't reset the failure count")
+	}
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	now := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	for _, b := range []*CircuitBreaker{
+		NewCircuitBreaker(0, time.Minute),
+		NewCircuitBreaker(1, 0),
+	} {
+		for range 5 {
+			b.Failure(now)
+		}
+		if ok, _ := b.Allow(now); !ok {
+			t.Errorf("disabled breaker %+v opened", b)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Valid values for CapacityUnit
+var capacityUnits = []string{"bytes", "kb", "mb"}
+
+// Formats the mempool capacity in unit. Scaled values are followed by the
+// exact number of bytes
+func formatCapacity(capacity uint32, unit string) string {
+	var scaled float64
+	var suffix string
+	switch unit {
+	case "kb":
+		scaled, suffix = float64(capacity)/1000, "KB"
+	case "mb":
+		scaled, suffix = float64(capacity)/1000/1000, "MB"
+	default:
+		return strconv.FormatUint(uint64(capacity), 10)
+	}
+	return fmt.Sprintf(
+		"%s %s (%d bytes)",
+		strconv.FormatFloat(scaled, 'f', 1, 64),
+		suffix,
+		capacity,
+	)
+}
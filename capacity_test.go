@@ -0,0 +1,33 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "testing"
+
+func TestFormatCapacity(t *testing.T) {
+	tests := []struct {
+		unit string
+		want string
+	}{
+		{"bytes", "1500000"},
+		{"kb", "1500.0 KB (1500000 bytes)"},
+		{"mb", "1.5 MB (1500000 bytes)"},
+	}
+	for _, test := range tests {
+		if got := formatCapacity(1500000, test.unit); got != test.want {
+			t.Errorf("%s: got %q, want %q", test.unit, got, test.want)
+		}
+	}
+}
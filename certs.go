@@ -0,0 +1,120 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	lcommon "github.com/blinklabs-io/gouroboros/ledger/common"
+)
+
+// How a kind of certificate is shown
+type certLabel struct {
+	Icon  string
+	Label string
+	// Short name for the legend
+	Legend string
+}
+
+// Certificate kinds we recognize, in the order they're shown in the legend
+var certKinds = []string{
+	"stake_registration",
+	"stake_deregistration",
+	"stake_delegation",
+	"pool_registration",
+	"pool_retirement",
+}
+
+// Default icons and labels for the certificate kinds we recognize. Each kind
+// has its own icon so operators can tell them apart at a glance. Icons can
+// be overridden per kind with CERT_ICONS
+var defaultCertLabels = map[string]certLabel{
+	"stake_registration": {
+		Icon:   "📝",
+		Label:  "Stake Registration",
+		Legend: "Stake Reg",
+	},
+	"stake_deregistration": {
+		Icon:   "🚫",
+		Label:  "Stake Deregistration",
+		Legend: "Stake Dereg",
+	},
+	"stake_delegation": {
+		Icon:   "🥩",
+		Label:  "Stake Delegation",
+		Legend: "Delegation",
+	},
+	"pool_registration": {
+		Icon:   "🏊",
+		Label:  "Pool Registration",
+		Legend: "Pool Reg",
+	},
+	"pool_retirement": {
+		Icon:   "🏁",
+		Label:  "Pool Retirement",
+		Legend: "Pool Retire",
+	},
+}
+
+// Returns the kind of a certificate as used in defaultCertLabels, or an
+// empty string for certificates we don't label
+func certificateKind(certificate lcommon.Certificate) string {
+	switch certificate.(type) {
+	case *lcommon.StakeRegistrationCertificate:
+		return "stake_registration"
+	case *lcommon.StakeDeregistrationCertificate:
+		return "stake_deregistration"
+	case *lcommon.StakeDelegationCertificate:
+		return "stake_delegation"
+	case *lcommon.PoolRegistrationCertificate:
+		return "pool_registration"
+	case *lcommon.PoolRetirementCertificate:
+		return "pool_retirement"
+	}
+	return ""
+}
+
+// Looks up the icon and label for a certificate, applying icon overrides
+func classifyCertificate(
+	certificate lcommon.Certificate,
+	iconOverrides map[string]string,
+) (certLabel, bool) {
+	return classifyCertificateKind(certificateKind(certificate), iconOverrides)
+}
+
+// Looks up the icon and label for a kind of certificate, applying icon
+// overrides
+func classifyCertificateKind(
+	kind string,
+	iconOverrides map[string]string,
+) (certLabel, bool) {
+	label, ok := defaultCertLabels[kind]
+	if !ok {
+		return certLabel{}, false
+	}
+	if icon, ok := iconOverrides[kind]; ok {
+		label.Icon = icon
+	}
+	return label, true
+}
+
+func validateCertIcons(iconOverrides map[string]string) error {
+	for kind := range iconOverrides {
+		if _, ok := defaultCertLabels[kind]; !ok {
+			return fmt.Errorf("unknown certificate kind in CERT_ICONS: %s", kind)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	lcommon "github.com/blinklabs-io/gouroboros/ledger/common"
+)
+
+func TestClassifyCertificate(t *testing.T) {
+	tests := []struct {
+		name        string
+		certificate lcommon.Certificate
+		overrides   map[string]string
+		wantLabel   string
+		wantIcon    string
+		wantOk      bool
+	}{
+		{
+			name:        "registration",
+			certificate: &lcommon.StakeRegistrationCertificate{},
+			wantLabel:   "Stake Registration",
+			wantIcon:    "📝",
+			wantOk:      true,
+		},
+		{
+			name:        "deregistration",
+			certificate: &lcommon.StakeDeregistrationCertificate{},
+			wantLabel:   "Stake Deregistration",
+			wantIcon:    "🚫",
+			wantOk:      true,
+		},
+		{
+			name:        "delegation",
+			certificate: &lcommon.StakeDelegationCertificate{},
+			wantLabel:   "Stake Delegation",
+			wantIcon:    "🥩",
+			wantOk:      true,
+		},
+		{
+			name:        "overridden",
+			certificate: &lcommon.StakeDelegationCertificate{},
+			overrides:   map[string]string{"stake_delegation": "🤝"},
+			wantLabel:   "Stake Delegation",
+			wantIcon:    "🤝",
+			wantOk:      true,
+		},
+		{
+			name:        "unrecognized",
+			certificate: &lcommon.MoveInstantaneousRewardsCertificate{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			label, ok := classifyCertificate(test.certificate, test.overrides)
+			if ok != test.wantOk || label.Label != test.wantLabel ||
+				label.Icon != test.wantIcon {
+				t.Errorf("got (%+v, %t)", label, ok)
+			}
+		})
+	}
+}
+
+func TestDefaultCertIconsDistinct(t *testing.T) {
+	seen := make(map[string]string)
+	for _, kind := range certKinds {
+		icon := defaultCertLabels[kind].Icon
+		if other, ok := seen[icon]; ok {
+			t.Errorf("%s and %s share icon %s", kind, other, icon)
+		}
+		seen[icon] = kind
+	}
+}
+
+func TestValidateCertIcons(t *testing.T) {
+	if err := validateCertIcons(
+		map[string]string{"pool_retirement": "x"},
+	); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := validateCertIcons(map[string]string{"bogus": "x"}); err == nil {
+		t.Error("expected an error for an unknown kind")
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	models "github.com/blinklabs-io/cardano-models"
+	"github.com/blinklabs-io/gouroboros/ledger"
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Decides the label and icon for a transaction. Classifiers return ok as
+// false for transactions they don't recognize
+type Classifier interface {
+	Classify(tx ledger.Transaction) (label string, icon string, ok bool)
+}
+
+// Adapts a function to the Classifier interface
+type ClassifierFunc func(tx ledger.Transaction) (string, string, bool)
+
+func (f ClassifierFunc) Classify(tx ledger.Transaction) (string, string, bool) {
+	return f(tx)
+}
+
+// Classifiers run after the built in ones, for forks to add their own
+// without editing them
+var extraClassifiers []Classifier
+
+// Adds a classifier to run after the built in ones
+func RegisterClassifier(classifier Classifier) {
+	extraClassifiers = append(extraClassifiers, classifier)
+}
+
+// Returns the classifiers in the order they're tried. Certificates take
+// precedence over governance proposals and votes, which take precedence
+// over stake addresses, which take precedence over script
+// addresses, which take precedence over known wallets, which take
+// precedence over metadata. Addresses and metadata are matched against
+// labels. Metadata larger than maxMetadataBytes isn't parsed, unless it's
+// zero
+func classifiers(
+	certIcons map[string]string,
+	labels labelTable,
+	wallets []knownWallet,
+	maxMetadataBytes int,
+) []Classifier {
+	builtin := []Classifier{
+		certClassifier(certIcons),
+		govClassifier(),
+		stakeAddressClassifier(labels),
+		addressClassifier(labels),
+		walletClassifier(wallets),
+		metadataClassifier(labels, maxMetadataBytes),
+	}
+	return append(builtin, extraClassifiers...)
+}
+
+// Builds the classifier chain for the loaded config, labels and wallets
+func configuredClassifiers() []Classifier {
+	cfg := GetConfig()
+	return classifiers(
+		cfg.App.CertIcons,
+		knownLabels,
+		knownWallets,
+		int(cfg.App.MaxMetadataBytes),
+	)
+}
+
+// Returns the label and icon from the first classifier recognizing tx
+func classifyTx(
+	tx ledger.Transaction,
+	classifiers []Classifier,
+) (string, string) {
+	for _, classifier := range classifiers {
+		if label, icon, ok := classifier.Classify(tx); ok {
+			return label, icon
+		}
+	}
+	return "", ""
+}
+
+// Matches the first line of CIP-20 message metadata against our list.
+// Metadata over maxBytes is skipped without parsing it, so huge blobs can't
+// slow down classifying, unless maxBytes is zero
+func metadataClassifier(labels labelTable, maxBytes int) Classifier {
+	return ClassifierFunc(func(tx ledger.Transaction) (string, string, bool) {
+		if tx.Metadata() == nil {
+			return "", "", false
+		}
+		metadataCbor := tx.Metadata().Cbor()
+		if maxBytes > 0 && len(metadataCbor) > maxBytes {
+			return "", "", false
+		}
+		var msgMetadata models.Cip20Metadata
+		_ = cbor.Unmarshal(metadataCbor, &msgMetadata)
+		if len(msgMetadata.Num674.Msg) == 0 {
+			return "", "", false
+		}
+		// Only check first line
+		icon := labels.icon(matchMessage, msgMetadata.Num674.Msg[0])
+		return "", icon, icon != ""
+	})
+}
+
+// Matches output addresses against known script addresses. The last
+// matching output wins
+func addressClassifier(labels labelTable) Classifier {
+	return ClassifierFunc(func(tx ledger.Transaction) (string, string, bool) {
+		outputs := tx.Outputs()
+		for i := len(outputs) - 1; i >= 0; i-- {
+			address := outputs[i].Address().String()
+			if icon := labels.icon(matchAddress, address); icon != "" {
+				return "", icon, true
+			}
+		}
+		return "", "", false
+	})
+}
+
+// Matches output stake addresses against known stake addresses. The last
+// matching output wins
+func stakeAddressClassifier(labels labelTable) Classifier {
+	return ClassifierFunc(func(tx ledger.Transaction) (string, string, bool) {
+		outputs := tx.Outputs()
+		for i := len(outputs) - 1; i >= 0; i-- {
+			stakeAddress := outputs[i].Address().StakeAddress()
+			if stakeAddress == nil {
+				continue
+			}
+			icon := labels.icon(matchStakeAddress, stakeAddress.String())
+			if icon != "" {
+				return "", icon, true
+			}
+		}
+		return "", "", false
+	})
+}
+
+// Matches certificates against known types, applying icon overrides
+func certClassifier(certIcons map[string]string) Classifier {
+	return ClassifierFunc(func(tx ledger.Transaction) (string, string, bool) {
+		for _, certificate := range tx.Certificates() {
+			if cert, ok := classifyCertificate(certificate, certIcons); ok {
+				return cert.Label, cert.Icon, true
+			}
+		}
+		return "", "", false
+	})
+}
@@ -0,0 +1,360 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	models "github.com/blinklabs-io/cardano-models"
+	"github.com/blinklabs-io/gouroboros/cbor"
+	"github.com/blinklabs-io/gouroboros/ledger"
+	lcommon "github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/blinklabs-io/gouroboros/ledger/shelley"
+)
+
+const (
+	minswapAddress = "addr1z84q0denmyep98ph3tmzwsmw0j7zau9ljmsqx6a4rvaau66j2c79gy9l76sdg0xwhd7r0c0kna0tycz4y5s6mlenh8pq777e2a"
+	sundaeAddress  = "addr1wxaptpmxcxawvr3pzlhgnpmzz3ql43n2tc8mn3av5kx0yzs09tqh8"
+	sealStake      = "stake1u8ffzkegp8h48mare3g3ntf3xmjce3jqptsdtj38ee3yh3c9t4uum"
+)
+
+// A transaction with just the fields the classifiers and record builders
+// look at. Anything else panics
+type fakeTx struct {
+	ledger.Transaction
+	hash         string
+	fee          uint64
+	ttl          uint64
+	metadata     *cbor.LazyValue
+	inputs       []lcommon.TransactionInput
+	outputs      []lcommon.TransactionOutput
+	certificates []lcommon.Certificate
+	proposals    []lcommon.ProposalProcedure
+	votes        lcommon.VotingProcedures
+	signers      []lcommon.Blake2b224
+	refInputs    []lcommon.TransactionInput
+}
+
+func (tx fakeTx) Hash() string                         { return tx.hash }
+func (tx fakeTx) Fee() uint64                          { return tx.fee }
+func (tx fakeTx) TTL() uint64                          { return tx.ttl }
+func (tx fakeTx) Metadata() *cbor.LazyValue            { return tx.metadata }
+func (tx fakeTx) Inputs() []lcommon.TransactionInput   { return tx.inputs }
+func (tx fakeTx) Outputs() []lcommon.TransactionOutput { return tx.outputs }
+func (tx fakeTx) Certificates() []lcommon.Certificate  { return tx.certificates }
+func (tx fakeTx) ProposalProcedures() []lcommon.ProposalProcedure {
+	return tx.proposals
+}
+func (tx fakeTx) VotingProcedures() lcommon.VotingProcedures { return tx.votes }
+func (tx fakeTx) RequiredSigners() []lcommon.Blake2b224      { return tx.signers }
+func (tx fakeTx) ReferenceInputs() []lcommon.TransactionInput {
+	return tx.refInputs
+}
+
+type fakeOutput struct {
+	lcommon.TransactionOutput
+	address lcommon.Address
+	amount  uint64
+}
+
+func (o fakeOutput) Address() lcommon.Address { return o.address }
+func (o fakeOutput) Amount() uint64           { return o.amount }
+
+func testOutput(t *testing.T, address string) lcommon.TransactionOutput {
+	t.Helper()
+	addr, err := lcommon.NewAddress(address)
+	if err != nil {
+		t.Fatalf("parsing %s: %s", address, err)
+	}
+	return fakeOutput{address: addr}
+}
+
+// Returns an output to a base address delegated to stakeAddress
+func testStakeOutput(
+	t *testing.T,
+	stakeAddress string,
+) lcommon.TransactionOutput {
+	t.Helper()
+	stake, err := lcommon.NewAddress(stakeAddress)
+	if err != nil {
+		t.Fatalf("parsing %s: %s", stakeAddress, err)
+	}
+	addr, err := lcommon.NewAddressFromParts(
+		lcommon.AddressTypeKeyKey,
+		lcommon.AddressNetworkMainnet,
+		make([]byte, lcommon.AddressHashSize),
+		stake.Bytes()[1:],
+	)
+	if err != nil {
+		t.Fatalf("building address: %s", err)
+	}
+	return fakeOutput{address: addr}
+}
+
+func testMetadata(t *testing.T, msg string) *cbor.LazyValue {
+	t.Helper()
+	data, err := cbor.Encode(
+		models.Cip20Metadata{Num674: models.Num674{Msg: []string{msg}}},
+	)
+	if err != nil {
+		t.Fatalf("encoding metadata: %s", err)
+	}
+	var metadata cbor.LazyValue
+	if err := metadata.UnmarshalCBOR(data); err != nil {
+		t.Fatalf("decoding metadata: %s", err)
+	}
+	return &metadata
+}
+
+func TestClassifyTxPrecedence(t *testing.T) {
+	tests := []struct {
+		name      string
+		tx        func(t *testing.T) fakeTx
+		wantLabel string
+		wantIcon  string
+	}{
+		{
+			name: "nothing known",
+			tx: func(t *testing.T) fakeTx {
+				return fakeTx{}
+			},
+		},
+		{
+			name: "metadata only",
+			tx: func(t *testing.T) fakeTx {
+				return fakeTx{
+					metadata: testMetadata(t, "Minswap: Deposit Order"),
+				}
+			},
+			wantIcon: "🐱",
+		},
+		{
+			name: "address beats metadata",
+			tx: func(t *testing.T) fakeTx {
+				return fakeTx{
+					metadata: testMetadata(t, "Minswap: Deposit Order"),
+					outputs: []lcommon.TransactionOutput{
+						testOutput(t, sundaeAddress),
+					},
+				}
+			},
+			wantIcon: "🍨",
+		},
+		{
+			name: "last matching address wins",
+			tx: func(t *testing.T) fakeTx {
+				return fakeTx{
+					outputs: []lcommon.TransactionOutput{
+						testOutput(t, sundaeAddress),
+						testOutput(t, minswapAddress),
+					},
+				}
+			},
+			wantIcon: "🐱",
+		},
+		{
+			name: "stake beats address",
+			tx: func(t *testing.T) fakeTx {
+				return fakeTx{
+					outputs: []lcommon.TransactionOutput{
+						testStakeOutput(t, sealStake),
+						testOutput(t, sundaeAddress),
+					},
+				}
+			},
+			wantIcon: "🦭",
+		},
+		{
+			name: "certificate beats stake and address",
+			tx: func(t *testing.T) fakeTx {
+				return fakeTx{
+					outputs: []lcommon.TransactionOutput{
+						testStakeOutput(t, sealStake),
+						testOutput(t, sundaeAddress),
+					},
+					certificates: []lcommon.Certificate{
+						&lcommon.PoolRegistrationCertificate{},
+					},
+				}
+			},
+			wantLabel: "Pool Registration",
+			wantIcon:  "🏊",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			label, icon := classifyTx(test.tx(t), classifiers(nil, knownLabels, nil, 0))
+			if label != test.wantLabel || icon != test.wantIcon {
+				t.Errorf(
+					"got (%q, %q), want (%q, %q)",
+					label,
+					icon,
+					test.wantLabel,
+					test.wantIcon,
+				)
+			}
+		})
+	}
+}
+
+func TestRegisteredClassifierRunsLast(t *testing.T) {
+	defer func(saved []Classifier) { extraClassifiers = saved }(
+		extraClassifiers,
+	)
+	RegisterClassifier(
+		ClassifierFunc(func(tx ledger.Transaction) (string, string, bool) {
+			return "Custom", "🧪", true
+		}),
+	)
+	label, icon := classifyTx(fakeTx{}, classifiers(nil, knownLabels, nil, 0))
+	if label != "Custom" || icon != "🧪" {
+		t.Errorf("got (%q, %q) for an unknown tx", label, icon)
+	}
+	tx := fakeTx{
+		outputs: []lcommon.TransactionOutput{
+			testOutput(t, sundaeAddress),
+		},
+	}
+	if _, icon := classifyTx(tx, classifiers(nil, knownLabels, nil, 0)); icon != "🍨" {
+		t.Errorf("custom classifier overrode a built in match: %q", icon)
+	}
+}
+
+func TestMetadataClassifierSizeCap(t *testing.T) {
+	metadata := testMetadata(t, "SSP: Swap Request")
+	tx := fakeTx{metadata: metadata}
+	size := len(metadata.Cbor())
+	tests := []struct {
+		name     string
+		maxBytes int
+		wantIcon string
+	}{
+		{"no cap", 0, "🍨"},
+		{"under the cap", size + 1, "🍨"},
+		{"at the cap", size, "🍨"},
+		{"over the cap", size - 1, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, icon, ok := metadataClassifier(knownLabels, test.maxBytes).Classify(tx)
+			if icon != test.wantIcon || ok != (test.wantIcon != "") {
+				t.Errorf("got (%q, %t), want %q", icon, ok, test.wantIcon)
+			}
+		})
+	}
+}
+
+func TestNewTxRecordUsesChain(t *testing.T) {
+	chain := []Classifier{
+		ClassifierFunc(func(tx ledger.Transaction) (string, string, bool) {
+			return "Custom", "🧪", true
+		}),
+	}
+	record := newTxRecord(fakeTx{}, ledger.TxTypeConway, 100, chain)
+	if record.Label != "Custom" || record.Icon != "🧪" {
+		t.Errorf(
+			"got (%q, %q), want the chain's label",
+			record.Icon,
+			record.Label,
+		)
+	}
+	record = newTxRecord(fakeTx{}, ledger.TxTypeConway, 100, nil)
+	if record.Label != "" || record.Icon != "" {
+		t.Errorf("got (%q, %q) without classifiers", record.Icon, record.Label)
+	}
+}
+
+func TestNewTxRecordSigners(t *testing.T) {
+	signer := func(b byte) lcommon.Blake2b224 {
+		return lcommon.NewBlake2b224(bytes.Repeat([]byte{b}, 28))
+	}
+	tests := []struct {
+		name    string
+		signers []lcommon.Blake2b224
+		want    int
+	}{
+		{"none", nil, 0},
+		{"one", []lcommon.Blake2b224{signer(1)}, 1},
+		{
+			"several",
+			[]lcommon.Blake2b224{signer(1), signer(2), signer(3)},
+			3,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			record := newTxRecord(
+				fakeTx{signers: test.signers},
+				ledger.TxTypeConway,
+				100,
+				nil,
+			)
+			if record.RequiredSigners != test.want {
+				t.Errorf(
+					"got %d signers, want %d",
+					record.RequiredSigners,
+					test.want,
+				)
+			}
+		})
+	}
+}
+
+func TestNewTxRecordReferenceInputs(t *testing.T) {
+	input := func(index int) lcommon.TransactionInput {
+		return shelley.NewShelleyTransactionInput(
+			strings.Repeat("ab", 32),
+			index,
+		)
+	}
+	tests := []struct {
+		name      string
+		refInputs []lcommon.TransactionInput
+		want      int
+	}{
+		{"none", nil, 0},
+		{"one", []lcommon.TransactionInput{input(0)}, 1},
+		{
+			"several",
+			[]lcommon.TransactionInput{input(0), input(1), input(2)},
+			3,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			record := newTxRecord(
+				fakeTx{refInputs: test.refInputs},
+				ledger.TxTypeConway,
+				100,
+				nil,
+			)
+			if record.ReferenceInputs != test.want {
+				t.Errorf(
+					"got %d reference inputs, want %d",
+					record.ReferenceInputs,
+					test.want,
+				)
+			}
+			if len(record.Inputs) != 0 {
+				t.Errorf(
+					"reference inputs counted as spent: %v",
+					record.Inputs,
+				)
+			}
+		})
+	}
+}
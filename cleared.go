@@ -0,0 +1,118 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Most transactions kept in the recently cleared list
+const maxRecentlyCleared = 10
+
+var recentlyCleared = NewClearedTracker(0, maxRecentlyCleared)
+
+// A transaction which has left the mempool, whether confirmed or evicted
+type clearedTx struct {
+	Record    TxRecord
+	ClearedAt time.Time
+}
+
+// Keeps a short list of transactions which recently left the mempool
+type ClearedTracker struct {
+	sync.Mutex
+	retention time.Duration
+	limit     int
+	previous  map[string]TxRecord
+	cleared   []clearedTx
+}
+
+// Creates a tracker keeping up to limit transactions for retention. A zero
+// retention disables tracking
+func NewClearedTracker(retention time.Duration, limit int) *ClearedTracker {
+	return &ClearedTracker{
+		retention: retention,
+		limit:     limit,
+	}
+}
+
+// Records the transactions missing from records since the last update,
+// drops those cleared longer than the retention ago, and returns the rest,
+// most recently cleared first
+func (c *ClearedTracker) Update(
+	records []TxRecord,
+	now time.Time,
+) []clearedTx {
+	if c.retention <= 0 {
+		return nil
+	}
+	c.Lock()
+	defer c.Unlock()
+	current := make(map[string]TxRecord, len(records))
+	for _, record := range records {
+		current[record.Hash] = record
+	}
+	var added []clearedTx
+	for hash, record := range c.previous {
+		if _, ok := current[hash]; !ok {
+			added = append(added, clearedTx{Record: record, ClearedAt: now})
+		}
+	}
+	// Map order is random, so sort for a stable list
+	sort.Slice(added, func(i, j int) bool {
+		return added[i].Record.Hash < added[j].Record.Hash
+	})
+	c.previous = current
+	cleared := append(added, c.cleared...)
+	kept := make([]clearedTx, 0, min(len(cleared), c.limit))
+	for _, tx := range cleared {
+		if len(kept) == c.limit {
+			break
+		}
+		if now.Sub(tx.ClearedAt) <= c.retention {
+			kept = append(kept, tx)
+		}
+	}
+	c.cleared = kept
+	return append([]clearedTx(nil), kept...)
+}
+
+// Formats the recently cleared transactions below the mempool, redacting
+// their hashes if enabled
+func FormatCleared(cleared []clearedTx, now time.Time, redact bool) string {
+	if len(cleared) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(" [white]Recently cleared:\n")
+	for _, tx := range cleared {
+		hash := tx.Record.Hash
+		if redact {
+			hash = redactHash(hash, redactKey)
+		}
+		sb.WriteString(
+			fmt.Sprintf(
+				" [gray]%-10s %-10d %s[white]\n",
+				formatTimestamp(tx.ClearedAt, now, relativeTimes.Load()),
+				tx.Record.Size,
+				hash,
+			),
+		)
+	}
+	return sb.String()
+}
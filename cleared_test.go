@@ -0,0 +1,59 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClearedTrackerUpdate(t *testing.T) {
+	start := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	c := NewClearedTracker(time.Minute, 2)
+	c.Update([]TxRecord{{Hash: "a"}, {Hash: "b"}, {Hash: "c"}}, start)
+	cleared := c.Update([]TxRecord{{Hash: "c"}}, start.Add(time.Second))
+	if len(cleared) != 2 || cleared[0].Record.Hash != "a" ||
+		cleared[1].Record.Hash != "b" {
+		t.Fatalf("got %+v", cleared)
+	}
+	cleared = c.Update(nil, start.Add(2*time.Second))
+	if len(cleared) != 2 || cleared[0].Record.Hash != "c" {
+		t.Fatalf("limit not applied, got %+v", cleared)
+	}
+	if cleared := c.Update(nil, start.Add(time.Hour)); len(cleared) != 0 {
+		t.Errorf("retention not applied, got %+v", cleared)
+	}
+	if cleared := NewClearedTracker(0, 2).Update(nil, start); cleared != nil {
+		t.Errorf("disabled tracker returned %+v", cleared)
+	}
+}
+
+func TestFormatClearedRedacts(t *testing.T) {
+	now := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	hash := strings.Repeat("ab", 32)
+	cleared := []clearedTx{{Record: TxRecord{Hash: hash}, ClearedAt: now}}
+	text := FormatCleared(cleared, now, false)
+	if !strings.Contains(text, hash) {
+		t.Errorf("hash missing without redaction: %q", text)
+	}
+	text = FormatCleared(cleared, now, true)
+	if strings.Contains(text, hash) {
+		t.Errorf("hash leaked with redaction: %q", text)
+	}
+	if !strings.Contains(text, redactHash(hash, redactKey)) {
+		t.Errorf("redacted hash missing: %q", text)
+	}
+}
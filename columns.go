@@ -0,0 +1,271 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// An optional column shown between the icon and the transaction hash
+type column struct {
+	header string
+	width  int
+	value  func(TxRecord) string
+	// Columns with a lower priority are dropped first on narrow terminals
+	priority int
+}
+
+var signersColumn = column{
+	header:   "Signers:",
+	width:    10,
+	priority: 5,
+	value: func(record TxRecord) string {
+		return strconv.Itoa(record.RequiredSigners)
+	},
+}
+
+var refInputsColumn = column{
+	header:   "RefIns:",
+	width:    10,
+	priority: 4,
+	value: func(record TxRecord) string {
+		return strconv.Itoa(record.ReferenceInputs)
+	},
+}
+
+// Marks transactions carrying auxiliary data, whether or not we recognized
+// its contents
+var metadataColumn = column{
+	header:   "Meta:",
+	width:    6,
+	priority: 2,
+	value: func(record TxRecord) string {
+		if record.HasMetadata {
+			return "yes"
+		}
+		return ""
+	},
+}
+
+// Returns the optional columns enabled in the config, in display order
+func optionalColumns(cfg *Config) []column {
+	var columns []column
+	if cfg.App.ShowSigners {
+		columns = append(columns, signersColumn)
+	}
+	if cfg.App.ShowRefInputs {
+		columns = append(columns, refInputsColumn)
+	}
+	if cfg.App.ShowRedeemers {
+		columns = append(columns, redeemersColumn)
+	}
+	if cfg.App.ShowEra {
+		columns = append(columns, eraColumn)
+	}
+	if cfg.App.ShowMetadata {
+		columns = append(columns, metadataColumn)
+	}
+	if cfg.App.ShowValue {
+		columns = append(columns, valueColumn)
+	}
+	if cfg.App.ShowFee {
+		columns = append(columns, feeColumn(cfg.App.FeeUnit))
+	}
+	if cfg.App.ShowFeeRate {
+		columns = append(columns, feeRateColumn(cfg.App.FeeRateUnit))
+	}
+	if cfg.App.ShowExpiry {
+		columns = append(columns, expiryColumn)
+	}
+	return columns
+}
+
+// Width of the leading position column
+const indexWidth = 6
+
+// Which parts of a transaction row are shown
+type rowLayout struct {
+	showIndex bool
+	showIcon  bool
+	columns   []column
+	// Rows to make stand out, or nil for none
+	highlight func(TxRecord) bool
+	// Whether any row carries the conflict marker before its hash
+	conflicts bool
+	// Cells the hash is cut to, or zero for the whole hash
+	hashWidth int
+}
+
+// Width of a transaction hash
+const hashWidth = 64
+
+// Fewest cells a hash is cut to on narrow terminals
+const minHashWidth = 24
+
+// Width of the hash in layout, which may be cut short
+func (l rowLayout) shownHashWidth() int {
+	if l.hashWidth > 0 {
+		return l.hashWidth
+	}
+	return hashWidth
+}
+
+// Width of a row in layout, including the leading space
+func (l rowLayout) width(sep string) int {
+	sepWidth := tview.TaggedStringWidth(sep)
+	width := 1 + 10 + sepWidth + l.shownHashWidth()
+	if l.showIndex {
+		width += indexWidth + sepWidth
+	}
+	if l.showIcon {
+		width += 10 + sepWidth
+	}
+	for _, col := range l.columns {
+		width += col.width + sepWidth
+	}
+	if l.conflicts {
+		width += tview.TaggedStringWidth(conflictIcon + " ")
+	}
+	return width
+}
+
+// Drops parts of layout until rows fit in width cells, rather than letting
+// them wrap. Optional columns go first, from the lowest priority up, then
+// the hash is cut short, down to minHashWidth, and only then is the icon
+// dropped. The size is always kept. A width of zero or less means the
+// terminal width is unknown, so nothing is dropped
+func fitLayout(layout rowLayout, sep string, width int) rowLayout {
+	if width <= 0 || layout.width(sep) <= width {
+		return layout
+	}
+	columns := slices.Clone(layout.columns)
+	for len(columns) > 0 {
+		layout.columns = columns
+		if layout.width(sep) <= width {
+			return layout
+		}
+		lowest := 0
+		for i, col := range columns {
+			if col.priority < columns[lowest].priority {
+				lowest = i
+			}
+		}
+		columns = slices.Delete(columns, lowest, lowest+1)
+	}
+	layout.columns = nil
+	if fitHash(&layout, sep, width) {
+		return layout
+	}
+	layout.showIcon = false
+	fitHash(&layout, sep, width)
+	return layout
+}
+
+// Cuts the hash in layout short so rows fit in width cells, reporting
+// whether they do. The hash is never cut below minHashWidth
+func fitHash(layout *rowLayout, sep string, width int) bool {
+	layout.hashWidth = 0
+	over := layout.width(sep) - width
+	if over <= 0 {
+		return true
+	}
+	layout.hashWidth = max(hashWidth-over, minHashWidth)
+	return layout.width(sep) <= width
+}
+
+// Cuts hash to fit in width cells, marking the cut with an ellipsis
+func truncateHash(hash string, width int) string {
+	if width <= 0 || len(hash) <= width {
+		return hash
+	}
+	return hash[:width-1] + "…"
+}
+
+func formatHeaderRow(sep string, layout rowLayout) string {
+	var sb strings.Builder
+	sb.WriteString(" [white]")
+	if layout.showIndex {
+		sb.WriteString(fmt.Sprintf("%-*s%s", indexWidth, "#", sep))
+	}
+	sb.WriteString(fmt.Sprintf("%-10s%s", "Size:", sep))
+	if layout.showIcon {
+		sb.WriteString(fmt.Sprintf("%-10s%s", "Icon:", sep))
+	}
+	for _, col := range layout.columns {
+		sb.WriteString(fmt.Sprintf("%-*s%s", col.width, col.header, sep))
+	}
+	sb.WriteString("TxHash:\n")
+	return sb.String()
+}
+
+// Builds a transaction row with columns joined by sep. The row's position
+// in the list is shown first when the layout includes the index
+func formatRow(
+	sep string,
+	layout rowLayout,
+	record TxRecord,
+	index int,
+) string {
+	var sb strings.Builder
+	sb.WriteString(" [white]")
+	if layout.showIndex {
+		sb.WriteString(fmt.Sprintf("%-*d%s", indexWidth, index, sep))
+	}
+	sb.WriteString(fmt.Sprintf("%-10d%s", record.Size, sep))
+	if layout.showIcon {
+		// Icons are a single rune but two cells wide
+		iconWidth := 10
+		if record.Icon != "" {
+			iconWidth = 9
+		}
+		sb.WriteString(fmt.Sprintf("%-*s%s", iconWidth, record.Icon, sep))
+	}
+	for _, col := range layout.columns {
+		sb.WriteString(fmt.Sprintf("%-*s%s", col.width, col.value(record), sep))
+	}
+	if record.Conflict {
+		sb.WriteString(warningTag() + conflictIcon + " ")
+	}
+	hashColor := "[blue]"
+	if layout.highlight != nil && layout.highlight(record) {
+		hashColor = highlightTag()
+	}
+	sb.WriteString(
+		fmt.Sprintf(
+			"%s%s[white:-]\n",
+			hashColor,
+			truncateHash(record.Hash, layout.hashWidth),
+		),
+	)
+	return sb.String()
+}
+
+// Valid values for IndexMode
+var indexModes = []string{"global", "page"}
+
+// Returns the number shown for the row at position, counting from zero, and
+// the page it's on. Global numbering counts through the whole list, while
+// page numbering restarts every pageSize rows
+func rowIndex(position int, mode string, pageSize int) (int, int) {
+	if mode != "page" || pageSize <= 0 {
+		return position + 1, 1
+	}
+	return position%pageSize + 1, position/pageSize + 1
+}
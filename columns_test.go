@@ -0,0 +1,241 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rivo/tview"
+)
+
+func TestRowIndex(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      string
+		pageSize  int
+		positions []int
+		wantIndex []int
+		wantPage  []int
+	}{
+		{
+			name:      "global",
+			mode:      "global",
+			pageSize:  2,
+			positions: []int{0, 1, 2, 4},
+			wantIndex: []int{1, 2, 3, 5},
+			wantPage:  []int{1, 1, 1, 1},
+		},
+		{
+			name:      "page",
+			mode:      "page",
+			pageSize:  2,
+			positions: []int{0, 1, 2, 4},
+			wantIndex: []int{1, 2, 1, 1},
+			wantPage:  []int{1, 1, 2, 3},
+		},
+		{
+			name:      "page without a size",
+			mode:      "page",
+			pageSize:  0,
+			positions: []int{0, 5},
+			wantIndex: []int{1, 6},
+			wantPage:  []int{1, 1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for i, position := range test.positions {
+				index, page := rowIndex(position, test.mode, test.pageSize)
+				if index != test.wantIndex[i] || page != test.wantPage[i] {
+					t.Errorf(
+						"position %d: got (%d, %d), want (%d, %d)",
+						position,
+						index,
+						page,
+						test.wantIndex[i],
+						test.wantPage[i],
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatTransactionsNumbering(t *testing.T) {
+	records := []TxRecord{
+		{Hash: "aaa", Size: 3},
+		{Hash: "bbb", Size: 2},
+		{Hash: "ccc", Size: 1},
+	}
+	tests := []struct {
+		mode      string
+		wantIndex []string
+		wantPages bool
+	}{
+		{"global", []string{"1", "2", "3"}, false},
+		{"page", []string{"1", "2", "1"}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.mode, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.App.ShowIndex = true
+			cfg.App.IndexMode = test.mode
+			cfg.App.PageSize = 2
+			text := FormatTransactions(cfg, records)
+			var indexes []string
+			for _, line := range strings.Split(text, "\n") {
+				for _, hash := range []string{"aaa", "bbb", "ccc"} {
+					if strings.Contains(line, hash) {
+						index := strings.Fields(line)[0]
+						indexes = append(
+							indexes,
+							strings.TrimPrefix(index, "[white]"),
+						)
+					}
+				}
+			}
+			if strings.Join(indexes, " ") != strings.Join(test.wantIndex, " ") {
+				t.Errorf("got indexes %v, want %v", indexes, test.wantIndex)
+			}
+			if strings.Contains(text, "Page 2") != test.wantPages {
+				t.Errorf("page divider mismatch in %q", text)
+			}
+		})
+	}
+}
+
+func TestFitLayout(t *testing.T) {
+	full := rowLayout{
+		showIcon: true,
+		columns:  []column{signersColumn, metadataColumn},
+	}
+	conflicting := full
+	conflicting.conflicts = true
+	markerWidth := tview.TaggedStringWidth(conflictIcon + " ")
+	tests := []struct {
+		name        string
+		layout      rowLayout
+		width       int
+		wantIcon    bool
+		wantColumns []string
+		wantHash    int
+	}{
+		{"unknown width", full, 0, true, []string{"Signers:", "Meta:"}, 64},
+		{"wide", full, 120, true, []string{"Signers:", "Meta:"}, 64},
+		{"icon and full hash", full, 87, true, nil, 64},
+		{"80 columns", full, 80, true, nil, 57},
+		{"narrow", full, 40, false, nil, 28},
+		{"conflict marker", conflicting, 87, true, nil, 64 - markerWidth},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			layout := fitLayout(test.layout, " ", test.width)
+			var headers []string
+			for _, col := range layout.columns {
+				headers = append(headers, col.header)
+			}
+			if layout.showIcon != test.wantIcon {
+				t.Errorf("got icon %t, want %t", layout.showIcon, test.wantIcon)
+			}
+			if !slices.Equal(headers, test.wantColumns) {
+				t.Errorf("got columns %v, want %v", headers, test.wantColumns)
+			}
+			if got := layout.shownHashWidth(); got != test.wantHash {
+				t.Errorf("got hash width %d, want %d", got, test.wantHash)
+			}
+			if test.width > 0 && layout.width(" ") > test.width {
+				t.Errorf(
+					"got row width %d, want at most %d",
+					layout.width(" "),
+					test.width,
+				)
+			}
+		})
+	}
+}
+
+func TestFormatRowTruncatesHash(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	layout := rowLayout{hashWidth: 28}
+	row := formatRow(" ", layout, TxRecord{Hash: hash, Size: 1}, 0)
+	if !strings.Contains(row, hash[:27]+"…") || strings.Contains(row, hash) {
+		t.Errorf("hash not cut to 28 cells: %q", row)
+	}
+}
+
+func TestColumnSeparator(t *testing.T) {
+	const sep = " │ "
+	layout := rowLayout{
+		showIndex: true,
+		showIcon:  true,
+		columns:   []column{signersColumn, metadataColumn},
+	}
+	hash := strings.Repeat("ab", 32)
+	record := TxRecord{Hash: hash, Size: 42, Icon: "🐱", RequiredSigners: 2}
+	lines := []string{
+		formatHeaderRow(sep, layout),
+		formatRow(sep, layout, record, 1),
+	}
+	// Index, size, icon and the two columns are each followed by sep
+	wantParts := 6
+	var offsets [][]int
+	for _, line := range lines {
+		parts := strings.Split(strings.TrimSuffix(line, "\n"), sep)
+		if len(parts) != wantParts {
+			t.Fatalf(
+				"got %d columns in %q, want %d",
+				len(parts),
+				line,
+				wantParts,
+			)
+		}
+		// The separators line up between the header and the rows
+		var lineOffsets []int
+		width := 0
+		for _, part := range parts[:len(parts)-1] {
+			width += tview.TaggedStringWidth(part)
+			lineOffsets = append(lineOffsets, width)
+			width += tview.TaggedStringWidth(sep)
+		}
+		offsets = append(offsets, lineOffsets)
+	}
+	if !slices.Equal(offsets[0], offsets[1]) {
+		t.Errorf(
+			"header separators at %v, row separators at %v",
+			offsets[0],
+			offsets[1],
+		)
+	}
+	if !strings.Contains(lines[1], hash) {
+		t.Errorf("hash missing from %q", lines[1])
+	}
+}
+
+func TestFormatTransactionsColumnSeparator(t *testing.T) {
+	cfg := &Config{}
+	cfg.App.ColumnSep = "|"
+	text := FormatTransactions(cfg, []TxRecord{{Hash: "aaa", Size: 3}})
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want a header and a row: %q", len(lines), text)
+	}
+	for _, line := range lines {
+		if got := strings.Count(line, "|"); got != 2 {
+			t.Errorf("got %d separators in %q, want 2", got, line)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+)
+
+// Number of hash characters shown per transaction in compact mode
+const compactHashLength = 8
+
+// Formats records as a grid of short hashes, each led by its icon and
+// colored by its category, fitting as many as width allows per line.
+// Records matching highlight, if set, stand out instead
+func formatCompactGrid(
+	records []TxRecord,
+	entries []legendEntry,
+	width int,
+	highlight func(TxRecord) bool,
+) string {
+	categories := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		categories[entry.Icon] = entry.Category
+	}
+	// Icon, space, hash and a gap before the next cell
+	const cellWidth = 2 + 1 + compactHashLength + 1
+	perLine := max((width-1)/cellWidth, 1)
+	var sb strings.Builder
+	for i, record := range records {
+		if i%perLine == 0 {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(" ")
+		}
+		icon := strings.TrimSpace(record.Icon)
+		if icon == "" {
+			icon = "  "
+		}
+		hash := record.Hash
+		if len(hash) > compactHashLength {
+			hash = hash[:compactHashLength]
+		}
+		if highlight != nil && highlight(record) {
+			sb.WriteString(icon + " " + highlightTag() + hash + "[white:-] ")
+			continue
+		}
+		category, ok := categories[icon]
+		if !ok && record.Icon != "" {
+			category = "other"
+		}
+		sb.WriteString(icon + " " + colorCategory(category, hash) + " ")
+	}
+	if len(records) > 0 {
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
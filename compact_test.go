@@ -0,0 +1,63 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatCompactGrid(t *testing.T) {
+	records := []TxRecord{
+		{Hash: strings.Repeat("a", 64), Icon: "🐱"},
+		{Hash: strings.Repeat("b", 64)},
+		{Hash: strings.Repeat("c", 64)},
+	}
+	// Room for two cells per line
+	text := formatCompactGrid(records, defaultLegendEntries, 30, nil)
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2: %q", len(lines), text)
+	}
+	if !strings.Contains(lines[0], "aaaaaaaa") ||
+		strings.Contains(lines[0], "aaaaaaaaa") {
+		t.Errorf("hash not shortened: %q", lines[0])
+	}
+	if !strings.Contains(lines[0], colorCategory("defi", "aaaaaaaa")) {
+		t.Errorf("labeled hash not colored by category: %q", lines[0])
+	}
+	if formatCompactGrid(nil, defaultLegendEntries, 30, nil) != "" {
+		t.Error("expected no output for no records")
+	}
+}
+
+func TestFormatCompactGridHighlight(t *testing.T) {
+	records := []TxRecord{
+		{Hash: strings.Repeat("a", 64)},
+		{Hash: strings.Repeat("b", 64)},
+	}
+	text := formatCompactGrid(
+		records,
+		nil,
+		80,
+		func(record TxRecord) bool { return record.Hash[0] == 'b' },
+	)
+	if !strings.Contains(text, highlightTag()+"bbbbbbbb") {
+		t.Errorf("highlighted cell missing: %q", text)
+	}
+	if strings.Contains(text, highlightTag()+"aaaaaaaa") {
+		t.Errorf("unmatched cell highlighted: %q", text)
+	}
+}
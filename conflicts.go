@@ -0,0 +1,72 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+)
+
+// Shown before the hash of a transaction in conflict
+const conflictIcon = "⚠"
+
+// Indexes transactions by the inputs they spend, as txhash#index, to the
+// hashes of the transactions spending them
+func inputIndex(records []TxRecord) map[string][]string {
+	index := make(map[string][]string)
+	for _, record := range records {
+		for _, input := range record.Inputs {
+			index[input] = append(index[input], record.Hash)
+		}
+	}
+	return index
+}
+
+// Returns the hashes of transactions spending an input which another
+// transaction also spends. At most one of them can make it into a block
+func findConflicts(records []TxRecord) map[string]bool {
+	conflicts := make(map[string]bool)
+	for _, hashes := range inputIndex(records) {
+		if len(hashes) < 2 {
+			continue
+		}
+		for _, hash := range hashes {
+			conflicts[hash] = true
+		}
+	}
+	return conflicts
+}
+
+// Flags the records in conflict, returning how many there are
+func markConflicts(records []TxRecord) int {
+	conflicts := findConflicts(records)
+	for i := range records {
+		records[i].Conflict = conflicts[records[i].Hash]
+	}
+	return len(conflicts)
+}
+
+// Formats the summary line for transactions in conflict, or nothing when
+// there are none
+func FormatConflicts(count int) string {
+	if count == 0 {
+		return ""
+	}
+	return fmt.Sprintf(
+		" %s%s Conflicting transactions: %d[white]\n",
+		warningTag(),
+		conflictIcon,
+		count,
+	)
+}
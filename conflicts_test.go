@@ -0,0 +1,67 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkConflicts(t *testing.T) {
+	records := []TxRecord{
+		{Hash: "a", Inputs: []string{"x#0", "y#0"}},
+		{Hash: "b", Inputs: []string{"y#0"}},
+		{Hash: "c", Inputs: []string{"y#1"}},
+		{Hash: "d", Inputs: []string{"z#0"}},
+	}
+	if count := markConflicts(records); count != 2 {
+		t.Errorf("got %d conflicts, want 2", count)
+	}
+	want := map[string]bool{"a": true, "b": true, "c": false, "d": false}
+	for _, record := range records {
+		if record.Conflict != want[record.Hash] {
+			t.Errorf(
+				"%s: got conflict %t, want %t",
+				record.Hash,
+				record.Conflict,
+				want[record.Hash],
+			)
+		}
+	}
+}
+
+func TestMarkConflictsNone(t *testing.T) {
+	records := []TxRecord{
+		{Hash: "a", Inputs: []string{"x#0"}, Conflict: true},
+		{Hash: "b", Inputs: []string{"x#1"}},
+	}
+	if count := markConflicts(records); count != 0 {
+		t.Errorf("got %d conflicts, want 0", count)
+	}
+	for _, record := range records {
+		if record.Conflict {
+			t.Errorf("%s: unexpected conflict", record.Hash)
+		}
+	}
+}
+
+func TestFormatConflicts(t *testing.T) {
+	if got := FormatConflicts(0); got != "" {
+		t.Errorf("expected nothing without conflicts, got %q", got)
+	}
+	if got := FormatConflicts(3); !strings.Contains(got, "3") {
+		t.Errorf("count not shown: %q", got)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sync"
+	"time"
+
+	ouroboros "github.com/blinklabs-io/gouroboros"
+)
+
+// Holds the node connection between refreshes, so it can be closed while
+// nothing is being fetched and dialed again when fetching resumes
+type NodeConnection struct {
+	sync.Mutex
+	conn         *ouroboros.Connection
+	lastActivity time.Time
+}
+
+var nodeConn = &NodeConnection{}
+
+// Held while reading from the mempool, so a sizes poll can't acquire or
+// release the mempool snapshot in the middle of a full refresh
+var mempoolMutex sync.Mutex
+
+// Returns the open connection, dialing the node if there isn't one. The
+// connection is reused across refreshes until it fails. Its errors are
+// passed on to errorChan
+func (n *NodeConnection) Get(
+	errorChan chan error,
+) (*ouroboros.Connection, error) {
+	n.Lock()
+	defer n.Unlock()
+	n.lastActivity = time.Now()
+	if n.conn != nil {
+		return n.conn, nil
+	}
+	// Each connection gets its own error channel, since it's closed along
+	// with the connection
+	connErrors := make(chan error, 10)
+	oConn, err := GetConnection(connErrors)
+	if err != nil {
+		return nil, err
+	}
+	n.conn = oConn
+	go n.forwardErrors(oConn, connErrors, errorChan)
+	return oConn, nil
+}
+
+// Passes errors from conn on to errorChan until conn shuts down, closing
+// it so the next Get dials again
+func (n *NodeConnection) forwardErrors(
+	conn *ouroboros.Connection,
+	connErrors <-chan error,
+	errorChan chan<- error,
+) {
+	for err := range connErrors {
+		// Closing waits for the connection's goroutines, which may be
+		// sending more errors, so keep draining them meanwhile
+		go n.closeIfCurrent(conn)
+		errorChan <- err
+	}
+}
+
+// Closes conn if it's still the open connection. An error from a
+// connection which was already replaced mustn't close its replacement
+func (n *NodeConnection) closeIfCurrent(conn *ouroboros.Connection) {
+	n.Lock()
+	defer n.Unlock()
+	if n.conn == conn {
+		n.close()
+	}
+}
+
+// Closes the connection, if open, so the next Get dials again
+func (n *NodeConnection) Close() {
+	n.Lock()
+	defer n.Unlock()
+	n.close()
+}
+
+func (n *NodeConnection) close() {
+	if n.conn == nil {
+		return
+	}
+	_ = n.conn.Close()
+	n.conn = nil
+}
+
+// Closes the connection if it hasn't been used for longer than timeout,
+// returning whether it was closed
+func (n *NodeConnection) CloseIfIdle(
+	now time.Time,
+	timeout time.Duration,
+) bool {
+	n.Lock()
+	defer n.Unlock()
+	if n.conn == nil || !isIdle(n.lastActivity, now, timeout) {
+		return false
+	}
+	n.close()
+	return true
+}
+
+// Reports whether the time since lastActivity exceeds timeout. A zero
+// timeout never expires
+func isIdle(
+	lastActivity time.Time,
+	now time.Time,
+	timeout time.Duration,
+) bool {
+	return timeout > 0 && now.Sub(lastActivity) > timeout
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	ouroboros "github.com/blinklabs-io/gouroboros"
+)
+
+func TestIsIdle(t *testing.T) {
+	last := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		timeout time.Duration
+		want    bool
+	}{
+		{"recent", time.Minute, 5 * time.Minute, false},
+		{"at timeout", 5 * time.Minute, 5 * time.Minute, false},
+		{"past timeout", 6 * time.Minute, 5 * time.Minute, true},
+		{"never expires", time.Hour, 0, false},
+	}
+	for _, test := range tests {
+		got := isIdle(last, last.Add(test.elapsed), test.timeout)
+		if got != test.want {
+			t.Errorf("%s: got %t, want %t", test.name, got, test.want)
+		}
+	}
+}
+
+func TestCloseIfIdleWithoutConnection(t *testing.T) {
+	n := &NodeConnection{}
+	if n.CloseIfIdle(time.Now(), time.Nanosecond) {
+		t.Error("reported closing a connection that wasn't open")
+	}
+}
+
+func TestForwardErrorsFromReplacedConnection(t *testing.T) {
+	n := &NodeConnection{}
+	stale := &ouroboros.Connection{}
+	connErrors := make(chan error, 2)
+	errorChan := make(chan error, 2)
+	connErrors <- errors.New("first")
+	connErrors <- errors.New("second")
+	close(connErrors)
+	// Returns once the stale connection's channel is closed, without
+	// touching it since it's no longer the open connection
+	n.forwardErrors(stale, connErrors, errorChan)
+	if len(errorChan) != 2 {
+		t.Fatalf("got %d errors forwarded, want 2", len(errorChan))
+	}
+	if err := <-errorChan; err.Error() != "first" {
+		t.Errorf("got %q first", err)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Window in which repeats of the same action key are ignored
+const keyRepeatWindow = 150 * time.Millisecond
+
+var keyDebouncer = NewDebouncer(keyRepeatWindow)
+
+// Keys whose repeats are coalesced, as holding them changes the sort or the
+// refresh interval many times a second. Other keys, such as q and p, always
+// act right away
+var debouncedKeys = []rune{'s', '+', '-'}
+
+// Reports whether a press of key at now should be acted on
+func allowKey(d *Debouncer, key rune, now time.Time) bool {
+	if !slices.Contains(debouncedKeys, key) {
+		return true
+	}
+	return d.Allow(key, now)
+}
+
+// Coalesces repeated events for the same key within a window, such as from a
+// held down key
+type Debouncer struct {
+	sync.Mutex
+	window time.Duration
+	last   map[rune]time.Time
+}
+
+func NewDebouncer(window time.Duration) *Debouncer {
+	return &Debouncer{
+		window: window,
+		last:   make(map[rune]time.Time),
+	}
+}
+
+// Reports whether an event for key at now should be acted on. Events within
+// the window of the last accepted event for the same key are dropped
+func (d *Debouncer) Allow(key rune, now time.Time) bool {
+	d.Lock()
+	defer d.Unlock()
+	if last, ok := d.last[key]; ok && now.Sub(last) < d.window {
+		return false
+	}
+	d.last[key] = now
+	return true
+}
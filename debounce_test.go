@@ -0,0 +1,74 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDebouncer(t *testing.T) {
+	start := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	d := NewDebouncer(100 * time.Millisecond)
+	tests := []struct {
+		key   rune
+		after time.Duration
+		want  bool
+	}{
+		{'p', 0, true},
+		{'p', 50 * time.Millisecond, false},
+		{'s', 60 * time.Millisecond, true},
+		{'p', 100 * time.Millisecond, true},
+		{'p', 150 * time.Millisecond, false},
+	}
+	for _, test := range tests {
+		if got := d.Allow(test.key, start.Add(test.after)); got != test.want {
+			t.Errorf(
+				"%c at %s: got %t, want %t",
+				test.key,
+				test.after,
+				got,
+				test.want,
+			)
+		}
+	}
+}
+
+func TestAllowKey(t *testing.T) {
+	start := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		key  rune
+		want bool
+	}{
+		{'s', false},
+		{'+', false},
+		{'-', false},
+		{'q', true},
+		{'p', true},
+		{'o', true},
+	}
+	for _, test := range tests {
+		t.Run(string(test.key), func(t *testing.T) {
+			d := NewDebouncer(100 * time.Millisecond)
+			if !allowKey(d, test.key, start) {
+				t.Fatal("first press was dropped")
+			}
+			repeat := start.Add(10 * time.Millisecond)
+			if got := allowKey(d, test.key, repeat); got != test.want {
+				t.Errorf("got repeat allowed %t, want %t", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Number of GetSizes responses kept for the debug page
+const sizesHistoryLength = 20
+
+var sizesHistory = NewSizesHistory(sizesHistoryLength)
+
+// A raw GetSizes response
+type sizesSample struct {
+	Time  time.Time
+	Sizes MempoolSizes
+}
+
+// Keeps the most recent GetSizes responses, for spotting discrepancies
+type SizesHistory struct {
+	sync.Mutex
+	samples    []sizesSample
+	maxSamples int
+}
+
+func NewSizesHistory(maxSamples int) *SizesHistory {
+	return &SizesHistory{
+		maxSamples: maxSamples,
+	}
+}
+
+func (h *SizesHistory) Add(now time.Time, sizes MempoolSizes) {
+	h.Lock()
+	defer h.Unlock()
+	h.samples = append(h.samples, sizesSample{Time: now, Sizes: sizes})
+	if len(h.samples) > h.maxSamples {
+		h.samples = h.samples[len(h.samples)-h.maxSamples:]
+	}
+}
+
+// Returns the kept samples, oldest first
+func (h *SizesHistory) Samples() []sizesSample {
+	h.Lock()
+	defer h.Unlock()
+	samples := make([]sizesSample, len(h.samples))
+	copy(samples, h.samples)
+	return samples
+}
+
+// Formats samples as a table, newest first
+func renderSizesTable(samples []sizesSample) string {
+	var sb strings.Builder
+	sb.WriteString(" [white]Raw GetSizes responses (newest first)\n\n")
+	sb.WriteString(
+		fmt.Sprintf(
+			" %-10s %-12s %-12s %-12s\n",
+			"Time:",
+			"Capacity:",
+			"Size:",
+			"Count:",
+		),
+	)
+	for i := len(samples) - 1; i >= 0; i-- {
+		sample := samples[i]
+		sb.WriteString(
+			fmt.Sprintf(
+				" %-10s [blue]%-12d %-12d %-12d[white]\n",
+				sample.Time.Format(time.TimeOnly),
+				sample.Sizes.Capacity,
+				sample.Sizes.Size,
+				sample.Sizes.NumberOfTxs,
+			),
+		)
+	}
+	if len(samples) == 0 {
+		sb.WriteString(" [gray]No responses yet[white]\n")
+	}
+	sb.WriteString("\n " + keyTag() + "(d)[white] Back\n")
+	return sb.String()
+}
+
+var debugText = tview.NewTextView().
+	SetDynamicColors(true)
+
+// Shows or hides the debug page. Runs on the tview event loop
+func toggleDebugPage() {
+	if pages.HasPage("Debug") {
+		pages.RemovePage("Debug")
+		return
+	}
+	debugText.SetText(renderSizesTable(sizesHistory.Samples()))
+	debugText.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 100 { // d
+			toggleDebugPage()
+			return nil
+		}
+		return event
+	})
+	pages.AddPage("Debug", debugText, true, true)
+}
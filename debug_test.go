@@ -0,0 +1,51 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSizesHistory(t *testing.T) {
+	start := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	h := NewSizesHistory(2)
+	for i := range 3 {
+		h.Add(
+			start.Add(time.Duration(i)*time.Second),
+			MempoolSizes{NumberOfTxs: uint32(i)},
+		)
+	}
+	samples := h.Samples()
+	if len(samples) != 2 || samples[0].Sizes.NumberOfTxs != 1 ||
+		samples[1].Sizes.NumberOfTxs != 2 {
+		t.Errorf("got %+v", samples)
+	}
+}
+
+func TestRenderSizesTable(t *testing.T) {
+	if !strings.Contains(renderSizesTable(nil), "No responses yet") {
+		t.Error("missing empty note")
+	}
+	start := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	text := renderSizesTable([]sizesSample{
+		{Time: start, Sizes: MempoolSizes{Capacity: 111}},
+		{Time: start.Add(time.Second), Sizes: MempoolSizes{Capacity: 222}},
+	})
+	if strings.Index(text, "222") > strings.Index(text, "111") {
+		t.Errorf("newest sample not first: %q", text)
+	}
+}
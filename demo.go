@@ -0,0 +1,71 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// Sample mempool used by demo mode, so txtop can be tried without a node
+//
+//go:embed demo/snapshot.json
+var demoSnapshotJson []byte
+
+type DemoSnapshot struct {
+	Capacity     uint32   `json:"capacity"`
+	Transactions []string `json:"transactions"`
+}
+
+// Decodes the bundled demo snapshot into its capacity and raw transactions
+func LoadDemoSnapshot() (uint32, [][]byte, error) {
+	var snapshot DemoSnapshot
+	if err := json.Unmarshal(demoSnapshotJson, &snapshot); err != nil {
+		return 0, nil, fmt.Errorf("failure decoding demo snapshot: %s", err)
+	}
+	txs := make([][]byte, 0, len(snapshot.Transactions))
+	for _, txHex := range snapshot.Transactions {
+		txRawBytes, err := hex.DecodeString(txHex)
+		if err != nil {
+			return 0, nil, fmt.Errorf(
+				"failure decoding demo transaction: %s",
+				err,
+			)
+		}
+		txs = append(txs, txRawBytes)
+	}
+	return snapshot.Capacity, txs, nil
+}
+
+// Builds a snapshot from the bundled demo data, classifying it the same as
+// transactions from a live node
+func GetDemoSnapshot() (Snapshot, error) {
+	capacity, txs, err := LoadDemoSnapshot()
+	if err != nil {
+		return Snapshot{}, err
+	}
+	var size int
+	for _, txRawBytes := range txs {
+		size += len(txRawBytes)
+	}
+	sizes := MempoolSizes{
+		Capacity:    capacity,
+		Size:        uint32(size),
+		NumberOfTxs: uint32(len(txs)),
+	}
+	return NewSnapshot(sizes, nil, txs, nil), nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDemoSnapshotWithoutNode(t *testing.T) {
+	cfg := testConfig()
+	cfg.App.Demo = true
+	cfg.Node.SocketPath = filepath.Join(t.TempDir(), "missing.socket")
+	cfg.Node.Address = ""
+	snapshot, err := GetSnapshot(cfg, make(chan error, 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(snapshot.Records) == 0 {
+		t.Fatal("got no transactions")
+	}
+	if snapshot.Sizes.NumberOfTxs != uint32(len(snapshot.Records)) {
+		t.Errorf(
+			"got %d transactions in the sizes, want %d",
+			snapshot.Sizes.NumberOfTxs,
+			len(snapshot.Records),
+		)
+	}
+	if snapshot.Sizes.Capacity == 0 {
+		t.Error("got no capacity")
+	}
+	var size, classified int
+	for _, record := range snapshot.Records {
+		if len(record.Hash) != 64 {
+			t.Errorf("got hash %q, want 64 hex digits", record.Hash)
+		}
+		if record.Size == 0 {
+			t.Errorf("transaction %s has no size", record.Hash)
+		}
+		size += record.Size
+		if record.Icon != "" {
+			classified++
+		}
+	}
+	if snapshot.Sizes.Size != uint32(size) {
+		t.Errorf(
+			"got mempool size %d, want the %d bytes of its transactions",
+			snapshot.Sizes.Size,
+			size,
+		)
+	}
+	if classified == 0 {
+		t.Error("no transaction was classified")
+	}
+}
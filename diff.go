@@ -0,0 +1,161 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Splits the transactions of two snapshots into those which entered the
+// mempool, left it, or stayed in it between them. Added and persisted
+// transactions keep the order of current, removed ones are sorted by hash
+func diffSnapshots(
+	previous Snapshot,
+	current Snapshot,
+) ([]TxRecord, []TxRecord, []TxRecord) {
+	added, removed := diffRecords(previous.Records, current.Records)
+	before := make(map[string]struct{}, len(previous.Records))
+	for _, record := range previous.Records {
+		before[record.Hash] = struct{}{}
+	}
+	var persisted []TxRecord
+	for _, record := range current.Records {
+		if _, ok := before[record.Hash]; ok {
+			persisted = append(persisted, record)
+		}
+	}
+	return added, removed, persisted
+}
+
+// Writes one section of a diff report, a count and total size followed by
+// a row per transaction starting with mark
+func writeDiffSection(
+	w io.Writer,
+	title string,
+	mark string,
+	records []TxRecord,
+) error {
+	var total int
+	for _, record := range records {
+		total += record.Size
+	}
+	_, err := fmt.Fprintf(
+		w,
+		"%s: %d transactions, %d bytes\n",
+		title,
+		len(records),
+		total,
+	)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		_, err := fmt.Fprintf(
+			w,
+			" %s %-10d %s\n",
+			mark,
+			record.Size,
+			record.Hash,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Writes the transactions added, removed and persisted between two
+// snapshots with their sizes, as plain text
+func writeDiffReport(w io.Writer, added, removed, persisted []TxRecord) error {
+	sections := []struct {
+		title   string
+		mark    string
+		records []TxRecord
+	}{
+		{"Added", "+", added},
+		{"Removed", "-", removed},
+		{"Persisted", "=", persisted},
+	}
+	for i, section := range sections {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		err := writeDiffSection(w, section.title, section.mark, section.records)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reads a snapshot, waits a refresh interval and reads another, then writes
+// the difference between them to path, or stdout when path is empty.
+// Returns the exit status, which is non-zero when the node couldn't be read
+func runDiff(cfg *Config, path string) int {
+	errorChan := make(chan error)
+	go func() {
+		for err := range errorChan {
+			fmt.Fprintf(os.Stderr, "connection error: %s\n", err)
+		}
+	}()
+	defer nodeConn.Close()
+	var snapshots [2]Snapshot
+	for i := range snapshots {
+		if i > 0 {
+			time.Sleep(cfg.App.Refresh.Duration())
+		}
+		snapshot, err := GetSnapshot(cfg, errorChan)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			return 1
+		}
+		if !snapshot.Healthy() {
+			fmt.Fprintf(os.Stderr, "failed to read the whole mempool\n")
+			return 1
+		}
+		if cfg.App.RedactHashes {
+			snapshot.Records = redactRecords(snapshot.Records, redactKey)
+		}
+		snapshots[i] = snapshot
+	}
+	added, removed, persisted := diffSnapshots(snapshots[0], snapshots[1])
+	if path == "" {
+		err := writeDiffReport(os.Stdout, added, removed, persisted)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write diff report: %s\n", err)
+			return 1
+		}
+		return 0
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create diff report: %s\n", err)
+		return 1
+	}
+	err = writeDiffReport(f, added, removed, persisted)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write diff report: %s\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	previous := Snapshot{Records: []TxRecord{
+		{Hash: "b", Size: 200},
+		{Hash: "a", Size: 100},
+		{Hash: "c", Size: 300},
+	}}
+	current := Snapshot{Records: []TxRecord{
+		{Hash: "d", Size: 400},
+		{Hash: "c", Size: 300},
+		{Hash: "a", Size: 100},
+	}}
+	added, removed, persisted := diffSnapshots(previous, current)
+	tests := []struct {
+		name    string
+		records []TxRecord
+		want    []string
+	}{
+		{"added", added, []string{"d"}},
+		{"removed", removed, []string{"b"}},
+		{"persisted", persisted, []string{"c", "a"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, record := range test.records {
+				got = append(got, record.Hash)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWriteDiffReport(t *testing.T) {
+	tests := []struct {
+		name      string
+		added     []TxRecord
+		removed   []TxRecord
+		persisted []TxRecord
+		want      string
+	}{
+		{
+			"empty",
+			nil,
+			nil,
+			nil,
+			"Added: 0 transactions, 0 bytes\n\n" +
+				"Removed: 0 transactions, 0 bytes\n\n" +
+				"Persisted: 0 transactions, 0 bytes\n",
+		},
+		{
+			"all sections",
+			[]TxRecord{{Hash: "d", Size: 400}, {Hash: "e", Size: 50}},
+			[]TxRecord{{Hash: "b", Size: 200}},
+			[]TxRecord{{Hash: "c", Size: 300}},
+			"Added: 2 transactions, 450 bytes\n" +
+				" + 400        d\n" +
+				" + 50         e\n" +
+				"\n" +
+				"Removed: 1 transactions, 200 bytes\n" +
+				" - 200        b\n" +
+				"\n" +
+				"Persisted: 1 transactions, 300 bytes\n" +
+				" = 300        c\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := writeDiffReport(
+				&buf,
+				test.added,
+				test.removed,
+				test.persisted,
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := buf.String(); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWriteDiffReportError(t *testing.T) {
+	err := writeDiffReport(failingWriter{}, nil, nil, nil)
+	if err == nil {
+		t.Error("expected the write error")
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Set up with DIM_AFTER in main
+var screenDimmer = NewDimmer(0, time.Now())
+
+// Dims the screen after a period without keypresses or mempool changes, to
+// reduce burn-in on always-on displays
+type Dimmer struct {
+	sync.Mutex
+	after        time.Duration
+	lastActivity time.Time
+	lastHash     string
+	dimmed       bool
+}
+
+// Creates a dimmer counting idle time from now. An after of zero never dims
+func NewDimmer(after time.Duration, now time.Time) *Dimmer {
+	return &Dimmer{
+		after:        after,
+		lastActivity: now,
+	}
+}
+
+// Records activity, such as a keypress, at now
+func (d *Dimmer) Activity(now time.Time) {
+	d.Lock()
+	defer d.Unlock()
+	d.lastActivity = now
+}
+
+// Records the mempool contents by their snapshot hash, counting a change as
+// activity
+func (d *Dimmer) Observe(hash string, now time.Time) {
+	d.Lock()
+	defer d.Unlock()
+	if hash != d.lastHash {
+		d.lastHash = hash
+		d.lastActivity = now
+	}
+}
+
+// Returns whether the screen should be dimmed at now, and whether that
+// differs from the last call
+func (d *Dimmer) Update(now time.Time) (bool, bool) {
+	d.Lock()
+	defer d.Unlock()
+	dimmed := d.after > 0 && now.Sub(d.lastActivity) >= d.after
+	changed := dimmed != d.dimmed
+	d.dimmed = dimmed
+	return dimmed, changed
+}
+
+// Dims or restores the text of every view. Color tags in the text only
+// change colors, so the dim attribute applies throughout. Views must only
+// be changed from the tview event loop
+func applyDim(dimmed bool) {
+	views := map[*tview.TextView]tcell.Style{
+		headerText: tcell.StyleDefault.Foreground(tcell.ColorGreen),
+		legendText: tcell.StyleDefault.Foreground(tcell.ColorGreen),
+		text:       tcell.StyleDefault,
+		watchText:  tcell.StyleDefault,
+		footerText: tcell.StyleDefault,
+	}
+	for view, style := range views {
+		view.SetTextStyle(style.Dim(dimmed))
+	}
+}
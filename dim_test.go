@@ -0,0 +1,59 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDimmer(t *testing.T) {
+	start := time.Now()
+	d := NewDimmer(time.Minute, start)
+	check := func(now time.Time, wantDimmed bool, wantChanged bool) {
+		t.Helper()
+		dimmed, changed := d.Update(now)
+		if dimmed != wantDimmed || changed != wantChanged {
+			t.Errorf(
+				"got dimmed %t, changed %t, want %t, %t",
+				dimmed,
+				changed,
+				wantDimmed,
+				wantChanged,
+			)
+		}
+	}
+	d.Observe("a", start)
+	check(start.Add(30*time.Second), false, false)
+	// An unchanged mempool isn't activity
+	d.Observe("a", start.Add(30*time.Second))
+	check(start.Add(time.Minute), true, true)
+	check(start.Add(2*time.Minute), true, false)
+	// A keypress restores the screen
+	d.Activity(start.Add(2 * time.Minute))
+	check(start.Add(2*time.Minute), false, true)
+	check(start.Add(3*time.Minute), true, true)
+	// So does a change in the mempool
+	d.Observe("b", start.Add(3*time.Minute))
+	check(start.Add(3*time.Minute), false, true)
+}
+
+func TestDimmerDisabled(t *testing.T) {
+	start := time.Now()
+	d := NewDimmer(0, start)
+	if dimmed, changed := d.Update(start.Add(time.Hour)); dimmed || changed {
+		t.Errorf("got dimmed %t, changed %t with dimming off", dimmed, changed)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Matches text escaped by tview.Escape, such as [title[], which is shown
+// as [title]
+var escapedTagPattern = regexp.MustCompile(`^\[([^\[\]]*)\[(\[*)\]$`)
+
+// Matches tview color tags such as [blue], [white:-] and [#d55e00::b], or
+// escaped text, which is tried first
+var tagPattern = regexp.MustCompile(
+	`\[[^\[\]]*\[\[*\]|` +
+		`\[([a-zA-Z]+|#[0-9a-fA-F]{6}|-)?` +
+		`(?::([a-zA-Z]+|#[0-9a-fA-F]{6}|-)?(?::([lbidrus]+|-)?)?)?\]`,
+)
+
+// ANSI codes for tview's text attributes
+var attributeCodes = map[rune]string{
+	'b': "1",
+	'd': "2",
+	'i': "3",
+	'u': "4",
+	'l': "5",
+	'r': "7",
+	's': "9",
+}
+
+// Returns the ANSI code setting a foreground or background color, given
+// the code resetting it to the terminal's default
+func colorCode(name string, reset string, base string) string {
+	if name == "-" {
+		return reset
+	}
+	r, g, b := tcell.GetColor(name).RGB()
+	if r < 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s;2;%d;%d;%d", base, r, g, b)
+}
+
+// Converts tview color tags in text to ANSI escape codes, or removes them
+// when color is false, so rendered text can be printed outside the UI
+func tagsToANSI(text string, color bool) string {
+	text = tagPattern.ReplaceAllStringFunc(text, func(tag string) string {
+		if escaped := escapedTagPattern.FindStringSubmatch(tag); escaped != nil {
+			return "[" + escaped[1] + escaped[2] + "]"
+		}
+		if !color {
+			return ""
+		}
+		parts := tagPattern.FindStringSubmatch(tag)
+		var codes []string
+		if parts[1] != "" {
+			codes = append(codes, colorCode(parts[1], "39", "38"))
+		}
+		if parts[2] != "" {
+			codes = append(codes, colorCode(parts[2], "49", "48"))
+		}
+		switch attributes := parts[3]; attributes {
+		case "":
+		case "-":
+			codes = append(codes, "22", "23", "24", "25", "27", "29")
+		default:
+			for _, attribute := range attributes {
+				codes = append(codes, attributeCodes[attribute])
+			}
+		}
+		var kept []string
+		for _, code := range codes {
+			if code != "" {
+				kept = append(kept, code)
+			}
+		}
+		if len(kept) == 0 {
+			return ""
+		}
+		return "\x1b[" + strings.Join(kept, ";") + "m"
+	})
+	if color {
+		// Leave the terminal as we found it
+		text += "\x1b[0m"
+	}
+	return text
+}
+
+// Renders the header and the mempool as the UI shows them
+func renderDump(cfg *Config, snapshot Snapshot) string {
+	return renderHeader(cfg) + RenderSnapshot(cfg, snapshot)
+}
+
+// Reads one snapshot and prints it as the UI would show it, as plain text
+// with ANSI colors when writing to a color terminal, without starting the
+// UI. It's set up by initFromConfig, as the UI is. Returns the exit status,
+// which is non-zero when the node couldn't be read
+func runDump(cfg *Config, w io.Writer) int {
+	errorChan := make(chan error)
+	go func() {
+		for err := range errorChan {
+			fmt.Fprintf(os.Stderr, "connection error: %s\n", err)
+		}
+	}()
+	defer nodeConn.Close()
+	snapshot, err := GetSnapshot(cfg, errorChan)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		return 1
+	}
+	setLastSnapshot(snapshot)
+	color := DetectTerminalCaps().Colors > 0
+	_, err = fmt.Fprint(w, tagsToANSI(renderDump(cfg, snapshot), color))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write snapshot: %s\n", err)
+		return 1
+	}
+	if !snapshot.Healthy() {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTagsToANSI(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		color bool
+		want  string
+	}{
+		{"plain", "size 42", false, "size 42"},
+		{"stripped", " [white]Size: [blue]42[white]", false, " Size: 42"},
+		{"background", "[yellow:-]hash[white:-]", false, "hash"},
+		{"escaped", "title [beta[] here", false, "title [beta] here"},
+		{
+			"named color",
+			"[red]x",
+			true,
+			"\x1b[38;2;255;0;0mx\x1b[0m",
+		},
+		{
+			"hex color and reset background",
+			"[#d55e00:-]x",
+			true,
+			"\x1b[38;2;213;94;0;49mx\x1b[0m",
+		},
+		{"attributes", "[::b]x[::-]", true, "\x1b[1mx\x1b[22;23;24;25;27;29m\x1b[0m"},
+		{"escaped in color", "[beta[]", true, "[beta]\x1b[0m"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := tagsToANSI(test.text, test.color); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRenderDump(t *testing.T) {
+	defer func(saved string) { _ = setSortBy(saved) }(getSortBy())
+	cfg := testConfig()
+	cfg.App.SortBy = "size"
+	cfg.App.MaxDisplayedTransactions = 2
+	if err := resetViewState(cfg); err != nil {
+		t.Fatal(err)
+	}
+	snapshot := Snapshot{
+		Time:  time.Now(),
+		Sizes: MempoolSizes{Capacity: 1000, Size: 600, NumberOfTxs: 3},
+		Records: []TxRecord{
+			{Hash: "small", Size: 100},
+			{Hash: "large", Size: 300},
+			{Hash: "medium", Size: 200},
+		},
+	}
+	got := tagsToANSI(renderDump(cfg, snapshot), false)
+	if strings.Contains(got, "[white]") || strings.Contains(got, "\x1b") {
+		t.Errorf("tags or escapes left in plain output:\n%s", got)
+	}
+	large := strings.Index(got, "large")
+	medium := strings.Index(got, "medium")
+	if large < 0 || medium < large {
+		t.Errorf("not sorted by size:\n%s", got)
+	}
+	if strings.Contains(got, "small") || !strings.Contains(got, "1 more") {
+		t.Errorf("MAX_DISPLAYED_TRANSACTIONS not applied:\n%s", got)
+	}
+}
+
+func TestRunDumpUsesConfig(t *testing.T) {
+	keepInitState(t)
+	defer setLastSnapshot(getLastSnapshot())
+	cfg := testConfig()
+	cfg.App.Demo = true
+	cfg.App.ShowPeak = true
+	cfg.App.TimeFormat = "relative"
+	cfg.App.Theme = "plain"
+	cfg.App.LabelsFile = writeLabelsFile(t, `[
+		{"match": "address", "value": "`+sundaeAddress+`", "icon": "🧁", "name": "Cupcake"}
+	]`)
+	if err := initFromConfig(cfg); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var out bytes.Buffer
+	if status := runDump(cfg, &out); status != 0 {
+		t.Fatalf("got exit status %d, want 0", status)
+	}
+	got := out.String()
+	if !strings.Contains(got, "🧁") || strings.Contains(got, "🍨") {
+		t.Errorf("labels file not applied:\n%s", got)
+	}
+	if !strings.Contains(got, " ago") {
+		t.Errorf("peak times not relative:\n%s", got)
+	}
+	if activeTheme != themes["plain"] {
+		t.Errorf("got theme %+v, want plain", activeTheme)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/blinklabs-io/gouroboros/ledger"
+)
+
+// Ledger era names by transaction type, as determined from the CBOR
+var txTypeEras = map[uint]string{
+	ledger.TxTypeByron:   "byron",
+	ledger.TxTypeShelley: "shelley",
+	ledger.TxTypeAllegra: "allegra",
+	ledger.TxTypeMary:    "mary",
+	ledger.TxTypeAlonzo:  "alonzo",
+	ledger.TxTypeBabbage: "babbage",
+	ledger.TxTypeConway:  "conway",
+}
+
+// Returns the era name for a transaction type, or "unknown"
+func txEra(txType uint) string {
+	if era, ok := txTypeEras[txType]; ok {
+		return era
+	}
+	return "unknown"
+}
+
+var eraColumn = column{
+	header:   "Era:",
+	width:    10,
+	priority: 3,
+	value: func(record TxRecord) string {
+		return record.Era
+	},
+}
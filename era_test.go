@@ -0,0 +1,38 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/blinklabs-io/gouroboros/ledger"
+)
+
+func TestTxEra(t *testing.T) {
+	tests := []struct {
+		txType uint
+		want   string
+	}{
+		{ledger.TxTypeByron, "byron"},
+		{ledger.TxTypeBabbage, "babbage"},
+		{ledger.TxTypeConway, "conway"},
+		{99, "unknown"},
+	}
+	for _, test := range tests {
+		if got := txEra(test.txType); got != test.want {
+			t.Errorf("%d: got %q, want %q", test.txType, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"net"
+)
+
+// Kinds of connection failure returned by GetConnection, for use with
+// errors.Is
+var (
+	ErrNoEndpoint = errors.New("no node endpoint configured")
+	ErrDialFailed = errors.New("failed to dial node")
+	ErrHandshake  = errors.New("failed to complete handshake with node")
+)
+
+// A connection failure which matches both its kind and underlying cause
+// with errors.Is and errors.As, while keeping a descriptive message
+type ConnectionError struct {
+	Kind error
+	Err  error
+	msg  string
+}
+
+func (e *ConnectionError) Error() string {
+	return e.msg
+}
+
+func (e *ConnectionError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+func newConnectionError(kind error, err error, msg string) error {
+	return &ConnectionError{
+		Kind: kind,
+		Err:  err,
+		msg:  msg,
+	}
+}
+
+// Determines whether an error from Dial happened while opening the
+// connection or during the handshake which follows it
+func dialErrorKind(err error) error {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return ErrDialFailed
+	}
+	return ErrHandshake
+}
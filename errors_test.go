@@ -0,0 +1,181 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+// Returns a TCP address which refuses connections
+func closedTCPPort(t *testing.T) *net.TCPAddr {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().(*net.TCPAddr)
+	listener.Close()
+	return addr
+}
+
+// Returns a TCP address which accepts connections and hangs up right away,
+// so dialing works but the handshake doesn't
+func hangUpTCPPort(t *testing.T) *net.TCPAddr {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr)
+}
+
+func TestGetConnectionErrors(t *testing.T) {
+	saved := *globalConfig
+	t.Cleanup(func() { *globalConfig = saved })
+	closed := closedTCPPort(t)
+	hangUp := hangUpTCPPort(t)
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		want   error
+	}{
+		{
+			"no endpoint",
+			func(cfg *Config) {},
+			ErrNoEndpoint,
+		},
+		{
+			"tcp without an address",
+			func(cfg *Config) {
+				cfg.App.Transport = "tcp"
+				cfg.Node.SocketPath = "/tmp/node.socket"
+			},
+			ErrNoEndpoint,
+		},
+		{
+			"missing socket",
+			func(cfg *Config) {
+				cfg.Node.SocketPath = filepath.Join(t.TempDir(), "node.socket")
+			},
+			ErrDialFailed,
+		},
+		{
+			"refused",
+			func(cfg *Config) {
+				cfg.Node.Address = closed.IP.String()
+				cfg.Node.Port = uint32(closed.Port)
+			},
+			ErrDialFailed,
+		},
+		{
+			"hung up during the handshake",
+			func(cfg *Config) {
+				cfg.Node.Address = hangUp.IP.String()
+				cfg.Node.Port = uint32(hangUp.Port)
+			},
+			ErrHandshake,
+		},
+	}
+	kinds := []error{ErrNoEndpoint, ErrDialFailed, ErrHandshake}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			*globalConfig = saved
+			globalConfig.App.Transport = "auto"
+			globalConfig.Node = NodeConfig{NetworkMagic: 764824073}
+			test.modify(globalConfig)
+			conn, err := GetConnection(nil)
+			if err == nil {
+				conn.Close()
+				t.Fatal("got no error")
+			}
+			for _, kind := range kinds {
+				if errors.Is(err, kind) != (kind == test.want) {
+					t.Errorf("errors.Is(%v) mismatch for %v", kind, err)
+				}
+			}
+			var connErr *ConnectionError
+			if !errors.As(err, &connErr) || connErr.Kind != test.want {
+				t.Errorf("got %#v, want a *ConnectionError", err)
+			}
+		})
+	}
+}
+
+func TestSelectTransport(t *testing.T) {
+	socket := NodeConfig{SocketPath: "/run/node.socket"}
+	tcp := NodeConfig{Address: "localhost", Port: 3001}
+	both := NodeConfig{
+		SocketPath: "/run/node.socket",
+		Address:    "localhost",
+		Port:       3001,
+	}
+	tests := []struct {
+		name       string
+		transport  string
+		node       NodeConfig
+		want       string
+		noEndpoint bool
+		wantErr    bool
+	}{
+		{"unix", "unix", socket, "unix", false, false},
+		{"unix without a socket", "unix", tcp, "", true, true},
+		{"tcp", "tcp", tcp, "tcp", false, false},
+		{"tcp without an address", "tcp", socket, "", true, true},
+		{
+			"tcp without a port",
+			"tcp",
+			NodeConfig{Address: "localhost"},
+			"",
+			true,
+			true,
+		},
+		{"auto with a socket", "auto", socket, "unix", false, false},
+		{"auto with an address", "auto", tcp, "tcp", false, false},
+		{"auto prefers tcp", "auto", both, "tcp", false, false},
+		{"auto without an endpoint", "auto", NodeConfig{}, "", true, true},
+		{"unknown", "udp", both, "", false, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := selectTransport(test.transport, test.node)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %t", err, test.wantErr)
+			}
+			if errors.Is(err, ErrNoEndpoint) != test.noEndpoint {
+				t.Errorf(
+					"got error %v, want ErrNoEndpoint %t",
+					err,
+					test.noEndpoint,
+				)
+			}
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
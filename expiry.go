@@ -0,0 +1,54 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+// Slots before a transaction's TTL at which it's shown as expiring. Slots
+// are a second long, so this is two minutes
+const expiringSlots = 120
+
+// Decides whether a transaction can still be included in a block, given
+// the slot of the chain tip and the transaction's TTL, the first slot it's
+// no longer valid in. A TTL of zero means the transaction doesn't expire.
+// The node only admits transactions whose validity interval has started,
+// so the lower bound isn't checked
+func expiryBadge(tipSlot uint64, ttl uint64) string {
+	switch {
+	case ttl == 0:
+		return "OK"
+	case tipSlot >= ttl:
+		return "EXPIRED"
+	case ttl-tipSlot <= expiringSlots:
+		return "EXPIRING"
+	default:
+		return "OK"
+	}
+}
+
+// Fills in the expiry badge of each record relative to the tip
+func markExpiry(records []TxRecord, tipSlot uint64) {
+	for i := range records {
+		records[i].Expiry = expiryBadge(tipSlot, records[i].TTL)
+	}
+}
+
+// Left blank when the tip couldn't be read
+var expiryColumn = column{
+	header:   "Expiry:",
+	width:    9,
+	priority: 2,
+	value: func(record TxRecord) string {
+		return record.Expiry
+	},
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+)
+
+func TestExpiryBadge(t *testing.T) {
+	tests := []struct {
+		name    string
+		tipSlot uint64
+		ttl     uint64
+		want    string
+	}{
+		{"no ttl", 1000, 0, "OK"},
+		{"far from ttl", 1000, 5000, "OK"},
+		{"just outside the margin", 1000, 1000 + expiringSlots + 1, "OK"},
+		{"at the margin", 1000, 1000 + expiringSlots, "EXPIRING"},
+		{"one slot left", 1000, 1001, "EXPIRING"},
+		{"at ttl", 1000, 1000, "EXPIRED"},
+		{"past ttl", 1000, 900, "EXPIRED"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := expiryBadge(test.tipSlot, test.ttl); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestMarkExpiry(t *testing.T) {
+	records := []TxRecord{{TTL: 0}, {TTL: 1050}, {TTL: 900}}
+	markExpiry(records, 1000)
+	for i, want := range []string{"OK", "EXPIRING", "EXPIRED"} {
+		if records[i].Expiry != want {
+			t.Errorf("record %d: got %q, want %q", i, records[i].Expiry, want)
+		}
+	}
+}
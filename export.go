@@ -0,0 +1,205 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	lcommon "github.com/blinklabs-io/gouroboros/ledger/common"
+)
+
+// Valid values for the --output flag
+var outputFormats = []string{"json", "csv"}
+
+// Valid values for AddressFormat
+var addressFormats = []string{"bech32", "hex", "both"}
+
+// A snapshot as written by --output
+type exportSnapshot struct {
+	Size         uint32     `json:"size"`
+	Capacity     uint32     `json:"capacity"`
+	NumberOfTxs  int        `json:"numberOfTxs"`
+	Transactions []exportTx `json:"transactions"`
+}
+
+type exportTx struct {
+	Hash            string `json:"hash"`
+	Size            int    `json:"size"`
+	Icon            string `json:"icon"`
+	Label           string `json:"label"`
+	HasMetadata     bool   `json:"hasMetadata"`
+	HasCertificates bool   `json:"hasCertificates"`
+	// The addresses paid to, in output order
+	Addresses []exportAddress `json:"addresses"`
+}
+
+// An address in the forms chosen by ADDRESS_FORMAT. Byron addresses are
+// base58 rather than bech32
+type exportAddress struct {
+	Bech32 string `json:"bech32,omitempty"`
+	Hex    string `json:"hex,omitempty"`
+}
+
+// Renders an address, as stored on records, in format. The hex form is
+// left out when the address can't be decoded
+func formatAddress(address string, format string) exportAddress {
+	var formatted exportAddress
+	if format != "hex" {
+		formatted.Bech32 = address
+	}
+	if format == "bech32" {
+		return formatted
+	}
+	decoded, err := lcommon.NewAddress(address)
+	if err != nil {
+		log.Printf("failed to decode address %s: %s", address, err)
+		return formatted
+	}
+	formatted.Hex = hex.EncodeToString(decoded.Bytes())
+	return formatted
+}
+
+func formatAddresses(addresses []string, format string) []exportAddress {
+	formatted := make([]exportAddress, 0, len(addresses))
+	for _, address := range addresses {
+		formatted = append(formatted, formatAddress(address, format))
+	}
+	return formatted
+}
+
+func newExportSnapshot(cfg *Config, snapshot Snapshot) exportSnapshot {
+	records := snapshot.Records
+	if cfg.App.RedactHashes {
+		records = redactRecords(records, redactKey)
+	}
+	entries := allLegendEntries(cfg)
+	txs := make([]exportTx, 0, len(records))
+	for _, record := range records {
+		txs = append(txs, exportTx{
+			Hash:            record.Hash,
+			Size:            record.Size,
+			Icon:            strings.TrimSpace(record.Icon),
+			Label:           resolvedLabel(entries, record),
+			HasMetadata:     record.HasMetadata,
+			HasCertificates: record.HasCertificates,
+			Addresses: formatAddresses(
+				record.Addresses,
+				cfg.App.AddressFormat,
+			),
+		})
+	}
+	return exportSnapshot{
+		Size:         snapshot.Sizes.Size,
+		Capacity:     snapshot.Sizes.Capacity,
+		NumberOfTxs:  snapshot.transactionCount(),
+		Transactions: txs,
+	}
+}
+
+// Writes a snapshot as JSON, or as CSV with a row per transaction. CSV
+// lists the addresses in each form separated by spaces, leaving the column
+// for a form which wasn't chosen empty
+func writeExport(w io.Writer, format string, export exportSnapshot) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(export)
+	case "csv":
+		writer := csv.NewWriter(w)
+		err := writer.Write([]string{
+			"hash",
+			"size",
+			"icon",
+			"label",
+			"hasMetadata",
+			"hasCertificates",
+			"addresses",
+			"addressesHex",
+		})
+		if err != nil {
+			return err
+		}
+		for _, tx := range export.Transactions {
+			var bech32, hexes []string
+			for _, address := range tx.Addresses {
+				if address.Bech32 != "" {
+					bech32 = append(bech32, address.Bech32)
+				}
+				if address.Hex != "" {
+					hexes = append(hexes, address.Hex)
+				}
+			}
+			err := writer.Write([]string{
+				tx.Hash,
+				strconv.Itoa(tx.Size),
+				tx.Icon,
+				tx.Label,
+				strconv.FormatBool(tx.HasMetadata),
+				strconv.FormatBool(tx.HasCertificates),
+				strings.Join(bech32, " "),
+				strings.Join(hexes, " "),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+}
+
+// Reads one snapshot and writes it to stdout in format, without starting
+// the UI. Returns the exit status, which is non-zero when the node couldn't
+// be read so scripts can tell
+func runExport(cfg *Config, format string) int {
+	errorChan := make(chan error)
+	go func() {
+		for err := range errorChan {
+			fmt.Fprintf(os.Stderr, "connection error: %s\n", err)
+		}
+	}()
+	defer nodeConn.Close()
+	snapshot, err := GetSnapshot(cfg, errorChan)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		return 1
+	}
+	if !snapshot.Healthy() {
+		if snapshot.SizesErr != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", snapshot.SizesErr)
+		}
+		if snapshot.TxErr != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", snapshot.TxErr)
+		}
+		return 1
+	}
+	export := newExportSnapshot(cfg, snapshot)
+	if err := writeExport(os.Stdout, format, export); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write snapshot: %s\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,215 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testExportSnapshot() exportSnapshot {
+	return newExportSnapshot(
+		testConfig(),
+		Snapshot{
+			Sizes:   MempoolSizes{Capacity: 1000, Size: 300, NumberOfTxs: 2},
+			Drained: 2,
+			Records: []TxRecord{
+				{
+					Hash:        "aa",
+					Size:        200,
+					Icon:        "🐱",
+					HasMetadata: true,
+					Addresses:   []string{sundaeAddress},
+				},
+				{
+					Hash:            "bb",
+					Size:            100,
+					Icon:            "🥩",
+					Label:           "Stake Delegation",
+					HasCertificates: true,
+				},
+			},
+		},
+	)
+}
+
+func TestWriteExportJSON(t *testing.T) {
+	var sb strings.Builder
+	if err := writeExport(&sb, "json", testExportSnapshot()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var got exportSnapshot
+	if err := json.Unmarshal([]byte(sb.String()), &got); err != nil {
+		t.Fatalf("invalid JSON: %s", err)
+	}
+	if got.Size != 300 || got.Capacity != 1000 || got.NumberOfTxs != 2 {
+		t.Errorf("wrong sizes: %+v", got)
+	}
+	if len(got.Transactions) != 2 {
+		t.Fatalf("got %d transactions, want 2", len(got.Transactions))
+	}
+	if tx := got.Transactions[0]; tx.Label != "Minswap" || !tx.HasMetadata {
+		t.Errorf("wrong first transaction: %+v", tx)
+	}
+	if tx := got.Transactions[1]; !tx.HasCertificates || tx.HasMetadata {
+		t.Errorf("wrong second transaction: %+v", tx)
+	}
+}
+
+func TestWriteExportCSV(t *testing.T) {
+	var sb strings.Builder
+	if err := writeExport(&sb, "csv", testExportSnapshot()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := "hash,size,icon,label,hasMetadata,hasCertificates," +
+		"addresses,addressesHex\n" +
+		"aa,200,🐱,Minswap,true,false," + sundaeAddress + ",\n" +
+		"bb,100,🥩,Stake Delegation,false,true,,\n"
+	if sb.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", sb.String(), want)
+	}
+}
+
+func TestWriteExportUnknownFormat(t *testing.T) {
+	var sb strings.Builder
+	if err := writeExport(&sb, "xml", testExportSnapshot()); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestFormatAddress(t *testing.T) {
+	const (
+		sundaeHex = "71ba158766c1bae60e2117ee8987621441fac66a5e0fb9c7" +
+			"aca58cf20a"
+		byronAddress = "DdzFFzCqrhsjcfsReoiHddNRaWsJWyPhGGpcAZHNcnE6BRYw" +
+			"KsHCs6ZoUtd6nf5kr8RdYsHVxz3x6GKdW4F9g7HrfeTqF2wdsMnVvB1G"
+		byronHex = "82d818584283581c2c9a3d50ac9fb7c4ad3906bf5d5804b46aff" +
+			"30f2763512b415227fafcdc54a113b9cd1266ca950fd2e50f756a09279ce" +
+			"61ef4447b6bd00853047034f09a3da08359ea69f"
+	)
+	tests := []struct {
+		name    string
+		address string
+		format  string
+		want    exportAddress
+	}{
+		{"bech32", sundaeAddress, "bech32", exportAddress{Bech32: sundaeAddress}},
+		{"hex", sundaeAddress, "hex", exportAddress{Hex: sundaeHex}},
+		{
+			"both",
+			sundaeAddress,
+			"both",
+			exportAddress{Bech32: sundaeAddress, Hex: sundaeHex},
+		},
+		{"byron hex", byronAddress, "hex", exportAddress{Hex: byronHex}},
+		{
+			"undecodable",
+			"addr1bogus",
+			"both",
+			exportAddress{Bech32: "addr1bogus"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := formatAddress(test.address, test.format)
+			if got != test.want {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestValidateAddressFormat(t *testing.T) {
+	cfg := testConfig()
+	cfg.App.AddressFormat = " HEX "
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cfg.App.AddressFormat != "hex" {
+		t.Errorf("not normalized: %q", cfg.App.AddressFormat)
+	}
+	cfg.App.AddressFormat = "base64"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected an error for an unknown address format")
+	}
+}
+
+// Demo transactions: one no built-in label matches, with the address it
+// pays, ones the Sundae and JPGstore labels match, and one whose icon
+// the table pads
+const (
+	demoUnlabeledHash    = "5e17d1fab7db8cfdb80d86d9e6bd007ed575d9bbd8c937056b3a224d5061d92c"
+	demoUnlabeledAddress = "addr1q9d34spgg2kdy47n82e7x9pdd6vql6d2engxmpj20jmhuc2047yqd4xnh7u6u5jp4t0q3fkxzckph4tgnzvamlu7k5psuahzcp"
+	demoSundaeHash       = "169e4901aeff194291e2e24c558e765dc1472f6636f2e3e6bae9a4406ddbee30"
+	demoJPGStoreHash     = "bd4e3e6682c3bd326deeea4f221c481821cb8afb45eccf2d7089b9ad7ebbc18b"
+	demoIndigoHash       = "06fb33e2dac84d3acc6cf11fbb995056ae877c1b55ae754d4f21f0cf58d72f33"
+)
+
+// Exports the demo snapshot as set up by initFromConfig for cfg
+func exportDemo(t *testing.T, cfg *Config) map[string]exportTx {
+	t.Helper()
+	cfg.App.Demo = true
+	if err := initFromConfig(cfg); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	snapshot, err := GetSnapshot(cfg, make(chan error, 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	txs := make(map[string]exportTx)
+	for _, tx := range newExportSnapshot(cfg, snapshot).Transactions {
+		txs[tx.Hash] = tx
+	}
+	return txs
+}
+
+func TestExportUsesWalletsFile(t *testing.T) {
+	keepInitState(t)
+	cfg := testConfig()
+	cfg.App.WalletsFile = writeWalletsFile(t, `[
+		{"address": "`+demoUnlabeledAddress+`", "name": "Test Exchange", "category": "exchange"}
+	]`)
+	tx := exportDemo(t, cfg)[demoUnlabeledHash]
+	if tx.Label != "Test Exchange" || tx.Icon != "🏦" {
+		t.Errorf("got %q %q, want the wallet", tx.Icon, tx.Label)
+	}
+}
+
+func TestExportUsesLabelsFile(t *testing.T) {
+	keepInitState(t)
+	cfg := testConfig()
+	cfg.App.LabelsFile = writeLabelsFile(t, `[
+		{"match": "address", "value": "`+sundaeAddress+`", "icon": "🧁", "name": "Cupcake"}
+	]`)
+	txs := exportDemo(t, cfg)
+	tx := txs[demoSundaeHash]
+	if tx.Icon != "🧁" || tx.Label != "Cupcake" {
+		t.Errorf("got %q %q, want the label from the file", tx.Icon, tx.Label)
+	}
+	// Built-in labels the file doesn't replace still apply
+	tx = txs[demoJPGStoreHash]
+	if tx.Icon != "🦛" || tx.Label != "JPGstore" {
+		t.Errorf("got %q %q, want the built-in label", tx.Icon, tx.Label)
+	}
+}
+
+func TestExportTrimsIconPadding(t *testing.T) {
+	keepInitState(t)
+	tx := exportDemo(t, testConfig())[demoIndigoHash]
+	if tx.Icon != "👁️" {
+		t.Errorf("got icon %q, want it without padding", tx.Icon)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Capacity reported for generated mempools, twice the mainnet block size
+const fakeCapacity = 180224
+
+// Shape of a generated mempool
+type fakeDataParams struct {
+	Seed    int64
+	Count   int
+	MinSize int
+	MaxSize int
+}
+
+// Builds a synthetic mempool, which is the same for a given seed apart
+// from being relative to now, so screenshots are reproducible and the UI
+// can be worked on without a node. Sizes are spread evenly between the
+// minimum and maximum, and roughly a quarter of the transactions are left
+// unlabeled
+func FakeSnapshot(params fakeDataParams, now time.Time) Snapshot {
+	rng := rand.New(rand.NewSource(params.Seed))
+	records := make([]TxRecord, 0, params.Count)
+	var size int
+	for i := 0; i < params.Count; i++ {
+		hash := make([]byte, 32)
+		_, _ = rng.Read(hash)
+		input := make([]byte, 32)
+		_, _ = rng.Read(input)
+		txSize := params.MinSize
+		if params.MaxSize > params.MinSize {
+			txSize += rng.Intn(params.MaxSize - params.MinSize + 1)
+		}
+		var icon string
+		if rng.Intn(4) > 0 {
+			entry := defaultLegendEntries[rng.Intn(len(defaultLegendEntries))]
+			icon = entry.Icon
+		}
+		records = append(records, TxRecord{
+			Hash: hex.EncodeToString(hash),
+			Size: txSize,
+			Icon: icon,
+			Era:  "conway",
+			Fee:  uint64(155381 + 44*txSize),
+			// Spread over the last few minutes, oldest first
+			FirstSeen: now.Add(
+				-time.Duration(params.Count-i) * 7 * time.Second,
+			),
+			Inputs: []string{fmt.Sprintf("%x#%d", input, rng.Intn(4))},
+		})
+		size += txSize
+	}
+	snapshot := Snapshot{
+		Time: now,
+		Sizes: MempoolSizes{
+			Capacity:    fakeCapacity,
+			Size:        uint32(size),
+			NumberOfTxs: uint32(len(records)),
+		},
+		Drained: len(records),
+		Records: records,
+	}
+	snapshot.Hash = snapshotHash(records)
+	snapshot.Conflicts = markConflicts(records)
+	return snapshot
+}
+
+// Returns the generated mempool shape from the config
+func fakeDataParamsFromConfig(cfg *Config) fakeDataParams {
+	return fakeDataParams{
+		Seed:    cfg.App.FakeSeed,
+		Count:   int(cfg.App.FakeCount),
+		MinSize: int(cfg.App.FakeMinSize),
+		MaxSize: int(cfg.App.FakeMaxSize),
+	}
+}
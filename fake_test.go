@@ -0,0 +1,91 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeSnapshot(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	params := fakeDataParams{Seed: 7, Count: 25, MinSize: 300, MaxSize: 900}
+	first := FakeSnapshot(params, now)
+	if len(first.Records) != params.Count {
+		t.Fatalf("got %d records, want %d", len(first.Records), params.Count)
+	}
+	if first.Sizes.NumberOfTxs != uint32(params.Count) {
+		t.Errorf(
+			"got %d reported transactions, want %d",
+			first.Sizes.NumberOfTxs,
+			params.Count,
+		)
+	}
+	var total int
+	for _, record := range first.Records {
+		if record.Size < params.MinSize || record.Size > params.MaxSize {
+			t.Errorf(
+				"%s: size %d outside %d-%d",
+				record.Hash,
+				record.Size,
+				params.MinSize,
+				params.MaxSize,
+			)
+		}
+		total += record.Size
+	}
+	if first.Sizes.Size != uint32(total) {
+		t.Errorf("got mempool size %d, want %d", first.Sizes.Size, total)
+	}
+	second := FakeSnapshot(params, now)
+	for i := range first.Records {
+		a, b := first.Records[i], second.Records[i]
+		if a.Hash != b.Hash || a.Size != b.Size || a.Icon != b.Icon {
+			t.Fatalf("record %d differs for the same seed", i)
+		}
+	}
+	params.Seed = 8
+	other := FakeSnapshot(params, now)
+	if other.Hash == first.Hash {
+		t.Errorf("expected a different mempool for a different seed")
+	}
+}
+
+func TestFakeSnapshotFixedSize(t *testing.T) {
+	params := fakeDataParams{Seed: 1, Count: 3, MinSize: 512, MaxSize: 512}
+	snapshot := FakeSnapshot(params, time.Now())
+	for _, record := range snapshot.Records {
+		if record.Size != 512 {
+			t.Errorf("got size %d, want 512", record.Size)
+		}
+	}
+}
+
+func TestFakeSnapshotEmpty(t *testing.T) {
+	params := fakeDataParams{Seed: 1, MinSize: 1, MaxSize: 2}
+	snapshot := FakeSnapshot(params, time.Now())
+	if len(snapshot.Records) != 0 || snapshot.Sizes.Size != 0 {
+		t.Errorf("expected an empty mempool, got %+v", snapshot.Sizes)
+	}
+}
+
+func TestValidateFakeSizes(t *testing.T) {
+	cfg := testConfig()
+	cfg.App.FakeMinSize = 1000
+	cfg.App.FakeMaxSize = 100
+	if err := cfg.Validate(); err == nil {
+		t.Errorf("expected an error for a minimum above the maximum")
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Valid values for FeeRateUnit
+var feeRateUnits = []string{"lovelace/byte", "ada/kb"}
+
+// Valid values for FeeUnit
+var feeUnits = []string{"ada", "lovelace"}
+
+// Formats a fee in lovelace, or in ADA down to the lovelace
+func formatFee(fee uint64, unit string) string {
+	if unit == "lovelace" {
+		return strconv.FormatUint(fee, 10)
+	}
+	return fmt.Sprintf("%d.%06d", fee/lovelacePerADA, fee%lovelacePerADA)
+}
+
+func feeColumn(unit string) column {
+	header := "Fee ADA:"
+	if unit == "lovelace" {
+		header = "Fee L:"
+	}
+	return column{
+		header:   header,
+		width:    10,
+		priority: 2,
+		value: func(record TxRecord) string {
+			return formatFee(record.Fee, unit)
+		},
+	}
+}
+
+// Formats the fee paid per unit of size, or "-" when the size is unknown
+func formatFeeRate(fee, size uint64, unit string) string {
+	if size == 0 {
+		return "-"
+	}
+	switch unit {
+	case "ada/kb":
+		// lovelace per byte is also microADA per byte, or milliADA per KB
+		adaPerKB := float64(fee) / float64(size) / 1000
+		return strconv.FormatFloat(adaPerKB, 'f', 4, 64)
+	default:
+		lovelacePerByte := float64(fee) / float64(size)
+		return strconv.FormatFloat(lovelacePerByte, 'f', 2, 64)
+	}
+}
+
+func feeRateColumn(unit string) column {
+	header := "L/byte:"
+	if unit == "ada/kb" {
+		header = "ADA/KB:"
+	}
+	return column{
+		header:   header,
+		width:    10,
+		priority: 1,
+		value: func(record TxRecord) string {
+			return formatFeeRate(record.Fee, uint64(record.Size), unit)
+		},
+	}
+}
+
+// Splits records into those paying a fee and those which don't, keeping
+// their order
+func partitionByFee(records []TxRecord) ([]TxRecord, []TxRecord) {
+	var paying, zeroFee []TxRecord
+	for _, record := range records {
+		if record.Fee > 0 {
+			paying = append(paying, record)
+		} else {
+			zeroFee = append(zeroFee, record)
+		}
+	}
+	return paying, zeroFee
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "testing"
+
+func TestFormatFeeRate(t *testing.T) {
+	tests := []struct {
+		fee  uint64
+		size uint64
+		unit string
+		want string
+	}{
+		{170000, 400, "lovelace/byte", "425.00"},
+		{170000, 400, "ada/kb", "0.4250"},
+		{170000, 0, "lovelace/byte", "-"},
+	}
+	for _, test := range tests {
+		got := formatFeeRate(test.fee, test.size, test.unit)
+		if got != test.want {
+			t.Errorf("%+v: got %q", test, got)
+		}
+	}
+}
+
+func TestFormatFee(t *testing.T) {
+	tests := []struct {
+		fee  uint64
+		unit string
+		want string
+	}{
+		{174389, "ada", "0.174389"},
+		{2_000_005, "ada", "2.000005"},
+		{0, "ada", "0.000000"},
+		{174389, "lovelace", "174389"},
+	}
+	for _, test := range tests {
+		if got := formatFee(test.fee, test.unit); got != test.want {
+			t.Errorf("%+v: got %q", test, got)
+		}
+	}
+}
+
+func TestValidateFeeUnit(t *testing.T) {
+	cfg := testConfig()
+	cfg.App.FeeUnit = " Lovelace "
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cfg.App.FeeUnit != "lovelace" {
+		t.Errorf("not normalized: %q", cfg.App.FeeUnit)
+	}
+	cfg.App.FeeUnit = "btc"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected an error for an unknown unit")
+	}
+}
+
+func TestPartitionByFee(t *testing.T) {
+	paying, zeroFee := partitionByFee([]TxRecord{
+		{Hash: "a", Fee: 1},
+		{Hash: "b"},
+		{Hash: "c", Fee: 2},
+	})
+	if len(paying) != 2 || paying[0].Hash != "a" || paying[1].Hash != "c" {
+		t.Errorf("got paying %+v", paying)
+	}
+	if len(zeroFee) != 1 || zeroFee[0].Hash != "b" {
+		t.Errorf("got zero fee %+v", zeroFee)
+	}
+}
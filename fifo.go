@@ -0,0 +1,78 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"sync/atomic"
+)
+
+// Writes each refresh's snapshot as a line of JSON to a named pipe, for
+// other local processes to read
+type FIFOWriter struct {
+	path string
+	// Set while a write is waiting on a slow reader
+	busy atomic.Bool
+}
+
+// Set up in main when FIFO_PATH is configured
+var fifoWriter *FIFOWriter
+
+// Creates the FIFO at path if it doesn't exist yet
+func NewFIFOWriter(path string) (*FIFOWriter, error) {
+	if err := ensureFIFO(path); err != nil {
+		return nil, err
+	}
+	return &FIFOWriter{path: path}, nil
+}
+
+// Writes data to the FIFO in the background. The data is dropped when no
+// reader is attached or the previous write hasn't finished, so a missing
+// or slow reader never holds up refreshing. Returns whether the write was
+// started
+func (f *FIFOWriter) Write(data []byte) (bool, error) {
+	if !f.busy.CompareAndSwap(false, true) {
+		return false, nil
+	}
+	file, err := openFIFO(f.path)
+	if err != nil || file == nil {
+		f.busy.Store(false)
+		return false, err
+	}
+	go func() {
+		defer f.busy.Store(false)
+		defer file.Close()
+		if _, err := file.Write(data); err != nil {
+			log.Printf("failed to write to FIFO: %s", err)
+		}
+	}()
+	return true, nil
+}
+
+// Writes the snapshot to the FIFO, if one is configured
+func publishSnapshot(cfg *Config, snapshot Snapshot) {
+	if fifoWriter == nil {
+		return
+	}
+	data, err := json.Marshal(newAPIMempool(cfg, snapshot))
+	if err != nil {
+		log.Printf("failed to encode snapshot for FIFO: %s", err)
+		return
+	}
+	if _, err := fifoWriter.Write(append(data, '\n')); err != nil {
+		log.Printf("failed to open FIFO: %s", err)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// Creates a FIFO at path, or checks the existing file is one
+func ensureFIFO(path string) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return syscall.Mkfifo(path, 0o600)
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeNamedPipe == 0 {
+		return fmt.Errorf("%s exists and is not a FIFO", path)
+	}
+	return nil
+}
+
+// Opens the FIFO for writing without waiting for a reader. Returns a nil
+// file when no reader is attached
+func openFIFO(path string) (*os.File, error) {
+	fd, err := syscall.Open(
+		path,
+		syscall.O_WRONLY|syscall.O_NONBLOCK|syscall.O_CLOEXEC,
+		0,
+	)
+	if errors.Is(err, syscall.ENXIO) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Writes block once a reader is attached, so large snapshots aren't cut
+	// short when the pipe buffer fills
+	if err := syscall.SetNonblock(fd, false); err != nil {
+		_ = syscall.Close(fd)
+		return nil, err
+	}
+	return os.NewFile(uintptr(fd), path), nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestFIFOWriterWithoutReader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "txtop.fifo")
+	writer, err := NewFIFOWriter(path)
+	if err != nil {
+		t.Fatalf("creating FIFO: %s", err)
+	}
+	started, err := writer.Write([]byte("dropped\n"))
+	if started || err != nil {
+		t.Errorf("got (%t, %v) with no reader", started, err)
+	}
+}
+
+func TestFIFOWriterWithReader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "txtop.fifo")
+	writer, err := NewFIFOWriter(path)
+	if err != nil {
+		t.Fatalf("creating FIFO: %s", err)
+	}
+	// Opening the read end non-blocking lets the writer find a reader
+	reader, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		t.Fatalf("opening reader: %s", err)
+	}
+	defer reader.Close()
+	started, err := writer.Write([]byte("snapshot\n"))
+	if !started || err != nil {
+		t.Fatalf("got (%t, %v) with a reader", started, err)
+	}
+	_ = reader.SetReadDeadline(time.Now().Add(time.Second))
+	line, err := bufio.NewReader(reader).ReadString('\n')
+	if err != nil || line != "snapshot\n" {
+		t.Errorf("got (%q, %v)", line, err)
+	}
+}
+
+func TestEnsureFIFORejectsRegularFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ensureFIFO(path); err == nil {
+		t.Error("expected an error for a regular file")
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Runtime filters applied to the transaction list
+type filterState struct {
+	// Only show transactions we couldn't match to a known protocol, to help
+	// discover addresses worth labeling
+	UnlabeledOnly bool
+	// Only show transactions with this legend entry's icon, or every
+	// transaction when empty
+	Label legendEntry
+}
+
+var filterMutex sync.Mutex
+var currentFilter filterState
+
+// Labels with transactions in the last snapshot, which n and N step through
+var currentPresentLabels []legendEntry
+
+func getFilterState() filterState {
+	filterMutex.Lock()
+	defer filterMutex.Unlock()
+	return currentFilter
+}
+
+func setFilterState(state filterState) {
+	filterMutex.Lock()
+	defer filterMutex.Unlock()
+	currentFilter = state
+}
+
+// Toggles showing only unlabeled transactions and returns the new value
+func toggleUnlabeledOnly() bool {
+	filterMutex.Lock()
+	defer filterMutex.Unlock()
+	currentFilter.UnlabeledOnly = !currentFilter.UnlabeledOnly
+	return currentFilter.UnlabeledOnly
+}
+
+// Advances the label filter to the entry after the current one, or back
+// to every label after the last, and returns it. Entries sharing an icon
+// are only visited once
+func cycleLabelFilter(entries []legendEntry) legendEntry {
+	filterMutex.Lock()
+	defer filterMutex.Unlock()
+	currentFilter.Label = nextLabel(entries, currentFilter.Label)
+	return currentFilter.Label
+}
+
+// Returns the entry after current in entries, skipping repeated icons, or
+// an empty entry for every label after the last one
+func nextLabel(entries []legendEntry, current legendEntry) legendEntry {
+	found := current.Icon == ""
+	var seen []string
+	for _, entry := range entries {
+		if slices.Contains(seen, entry.Icon) {
+			continue
+		}
+		seen = append(seen, entry.Icon)
+		if found {
+			return entry
+		}
+		found = entry.Icon == current.Icon
+	}
+	return legendEntry{}
+}
+
+// Records the labels with transactions in the latest snapshot
+func setPresentLabels(present []legendEntry) {
+	filterMutex.Lock()
+	defer filterMutex.Unlock()
+	currentPresentLabels = present
+}
+
+// Moves the label filter step labels through those present in the mempool,
+// wrapping around, and returns it
+func stepLabelFilter(step int) legendEntry {
+	filterMutex.Lock()
+	defer filterMutex.Unlock()
+	currentFilter.Label = stepLabel(
+		currentPresentLabels,
+		currentFilter.Label,
+		step,
+	)
+	return currentFilter.Label
+}
+
+// Returns the label step places after current in present, wrapping around.
+// Starts from the first label going forward, or the last going backward,
+// when current isn't present, and returns an empty entry for every label
+// when none are
+func stepLabel(
+	present []legendEntry,
+	current legendEntry,
+	step int,
+) legendEntry {
+	if len(present) == 0 {
+		return legendEntry{}
+	}
+	index := slices.IndexFunc(present, func(entry legendEntry) bool {
+		return entry.Icon == current.Icon
+	})
+	if index < 0 {
+		if step < 0 {
+			return present[len(present)-1]
+		}
+		return present[0]
+	}
+	index = (index + step) % len(present)
+	if index < 0 {
+		index += len(present)
+	}
+	return present[index]
+}
+
+// Returns the labels of records in legend order, followed by icons the
+// legend doesn't list, named after the transaction's label if it has one
+func presentLabels(entries []legendEntry, records []TxRecord) []legendEntry {
+	icons := make(map[string]string)
+	for _, record := range records {
+		// Some icons are padded with a space to fill two cells
+		if icon := strings.TrimSpace(record.Icon); icon != "" {
+			icons[icon] = record.Label
+		}
+	}
+	var present []legendEntry
+	for _, entry := range entries {
+		if _, ok := icons[entry.Icon]; ok {
+			present = append(present, entry)
+			delete(icons, entry.Icon)
+		}
+	}
+	unlisted := make([]string, 0, len(icons))
+	for icon := range icons {
+		unlisted = append(unlisted, icon)
+	}
+	slices.Sort(unlisted)
+	for _, icon := range unlisted {
+		name := icons[icon]
+		if name == "" {
+			name = icon
+		}
+		present = append(present, legendEntry{Icon: icon, Name: name})
+	}
+	return present
+}
+
+// Names the active label filter for the footer
+func labelFilterName(state filterState) string {
+	if state.Label.Icon == "" {
+		return "all"
+	}
+	return state.Label.Icon + " " + state.Label.Name
+}
+
+// A predicate deciding whether a transaction is shown
+type txFilter func(TxRecord) bool
+
+func isUnlabeled(record TxRecord) bool {
+	return record.Icon == "" && record.Label == ""
+}
+
+// Matches transactions with icon. Some icons are padded with a space to
+// fill two cells
+func hasIcon(icon string) txFilter {
+	return func(record TxRecord) bool {
+		return strings.TrimSpace(record.Icon) == icon
+	}
+}
+
+// Returns the predicates for the active filters
+func (s filterState) filters() []txFilter {
+	var filters []txFilter
+	if s.UnlabeledOnly {
+		filters = append(filters, isUnlabeled)
+	}
+	if s.Label.Icon != "" {
+		filters = append(filters, hasIcon(s.Label.Icon))
+	}
+	return filters
+}
+
+// Summarizes the active filters on one line, so it's clear why some
+// transactions aren't shown. Returns an empty string when none are active
+func describeFilters(state filterState) string {
+	var active []string
+	if state.UnlabeledOnly {
+		active = append(active, "unlabeled only")
+	}
+	if state.Label.Icon != "" {
+		active = append(active, "label "+labelFilterName(state))
+	}
+	if len(active) == 0 {
+		return ""
+	}
+	return " [white]Filters: " + keyTag() + strings.Join(active, ", ") +
+		"[white]\n"
+}
+
+// Returns the records which pass every filter
+func filterTransactions(records []TxRecord, filters []txFilter) []TxRecord {
+	if len(filters) == 0 {
+		return records
+	}
+	filtered := make([]TxRecord, 0, len(records))
+	for _, record := range records {
+		keep := true
+		for _, filter := range filters {
+			if !filter(record) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
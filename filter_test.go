@@ -0,0 +1,165 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFilterTransactions(t *testing.T) {
+	records := []TxRecord{
+		{Hash: "a", Icon: "🐱"},
+		{Hash: "b"},
+		{Hash: "c", Label: "Stake Delegation"},
+		{Hash: "d", Icon: "👁️ "},
+	}
+	tests := []struct {
+		name  string
+		state filterState
+		want  string
+	}{
+		{"none", filterState{}, "abcd"},
+		{"unlabeled only", filterState{UnlabeledOnly: true}, "b"},
+		{
+			"label",
+			filterState{Label: legendEntry{Icon: "🐱", Name: "Minswap"}},
+			"a",
+		},
+		{
+			"padded icon",
+			filterState{Label: legendEntry{Icon: "👁️", Name: "Indigo"}},
+			"d",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var hashes strings.Builder
+			for _, record := range filterTransactions(
+				records,
+				test.state.filters(),
+			) {
+				hashes.WriteString(record.Hash)
+			}
+			if hashes.String() != test.want {
+				t.Errorf("got %q, want %q", hashes.String(), test.want)
+			}
+		})
+	}
+}
+
+func TestDescribeFilters(t *testing.T) {
+	if got := describeFilters(filterState{}); got != "" {
+		t.Errorf("got %q with no filters", got)
+	}
+	got := describeFilters(filterState{UnlabeledOnly: true})
+	if !strings.Contains(got, "unlabeled only") {
+		t.Errorf("got %q", got)
+	}
+	got = describeFilters(
+		filterState{Label: legendEntry{Icon: "🐱", Name: "Minswap"}},
+	)
+	if !strings.Contains(got, "Minswap") {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestNextLabel(t *testing.T) {
+	entries := []legendEntry{
+		{Icon: "🐱", Name: "Minswap"},
+		{Icon: "🍨", Name: "Sundae"},
+		{Icon: "🐱", Name: "Minswap"},
+		{Icon: "🦸", Name: "Wingriders"},
+	}
+	var names []string
+	var current legendEntry
+	for range 5 {
+		current = nextLabel(entries, current)
+		names = append(names, labelFilterName(filterState{Label: current}))
+	}
+	got := strings.Join(names, ",")
+	want := "🐱 Minswap,🍨 Sundae,🦸 Wingriders,all,🐱 Minswap"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if next := nextLabel(nil, legendEntry{}); next.Icon != "" {
+		t.Errorf("got %+v without any labels", next)
+	}
+	gone := legendEntry{Icon: "🧪", Name: "Removed"}
+	if next := nextLabel(entries, gone); next.Icon != "" {
+		t.Errorf("got %+v after an unknown label, want all", next)
+	}
+}
+
+func TestStepLabel(t *testing.T) {
+	minswap := legendEntry{Icon: "🐱", Name: "Minswap"}
+	sundae := legendEntry{Icon: "🍨", Name: "Sundae"}
+	seal := legendEntry{Icon: "🦭", Name: "SealVM"}
+	present := []legendEntry{minswap, sundae, seal}
+	tests := []struct {
+		name    string
+		present []legendEntry
+		current legendEntry
+		step    int
+		want    legendEntry
+	}{
+		{"next from all", present, legendEntry{}, 1, minswap},
+		{"previous from all", present, legendEntry{}, -1, seal},
+		{"next", present, minswap, 1, sundae},
+		{"previous", present, sundae, -1, minswap},
+		{"wraps forward", present, seal, 1, minswap},
+		{"wraps backward", present, minswap, -1, seal},
+		{
+			"label no longer present",
+			present,
+			legendEntry{Icon: "🦸", Name: "Wingriders"},
+			1,
+			minswap,
+		},
+		{"single label", []legendEntry{sundae}, sundae, 1, sundae},
+		{"nothing present", nil, minswap, 1, legendEntry{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := stepLabel(test.present, test.current, test.step)
+			if got != test.want {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPresentLabels(t *testing.T) {
+	entries := []legendEntry{
+		{Icon: "🐱", Name: "Minswap"},
+		{Icon: "👁️", Name: "Indigo"},
+		{Icon: "🍨", Name: "Sundae"},
+	}
+	records := []TxRecord{
+		{Icon: "🍨"},
+		{Icon: "🧪", Label: "Custom"},
+		{},
+		{Icon: "👁️ "},
+		{Icon: "🍨"},
+	}
+	var names []string
+	for _, entry := range presentLabels(entries, records) {
+		names = append(names, entry.Name)
+	}
+	got := strings.Join(names, ",")
+	if want := "Indigo,Sundae,Custom"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
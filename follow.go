@@ -0,0 +1,93 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// A screen which was already initialized, so the application doesn't
+// initialize it again when it's set
+type initializedScreen struct {
+	tcell.Screen
+}
+
+func (initializedScreen) Init() error {
+	return nil
+}
+
+// Creates and initializes the screen for the UI. An error means the
+// terminal can't be used, such as in some containers and CI jobs, and
+// txtop should fall back to printing the mempool instead
+func startScreen(
+	newScreen func() (tcell.Screen, error),
+) (tcell.Screen, error) {
+	screen, err := newScreen()
+	if err != nil {
+		return nil, err
+	}
+	if err := screen.Init(); err != nil {
+		return nil, err
+	}
+	return initializedScreen{screen}, nil
+}
+
+// Renders one refresh of the mempool for the follow renderer
+func renderFollow(cfg *Config, content Content) string {
+	return renderHeader(cfg) + content.Main + "\n\n"
+}
+
+// Prints the mempool to w on every refresh until done is closed, for when
+// the UI can't start
+func runFollow(cfg *Config, w io.Writer, done <-chan struct{}) {
+	errorChan := make(chan error)
+	go func() {
+		for err := range errorChan {
+			connectionErrors.Add(1)
+			fmt.Fprintf(os.Stderr, "connection error: %s\n", err)
+		}
+	}()
+	color := DetectTerminalCaps().Colors > 0
+	interval := NewAdaptiveInterval(
+		cfg.App.Refresh.Duration(),
+		cfg.App.IdleMaxRefresh.Duration(),
+	)
+	refresh := func() (int, bool) {
+		fetchStart := time.Now()
+		content, err := safeFetch(
+			func() Content { return GetContent(cfg, errorChan) },
+			cfg.App.RecoverPanics,
+		)
+		if err != nil {
+			content = Content{
+				Main: fmt.Sprintf(" %sERROR: %s", errorTag(), err),
+			}
+		}
+		txCount := -1
+		snapshot := getLastSnapshot()
+		if !snapshot.Time.Before(fetchStart) {
+			txCount = snapshot.Drained
+		}
+		refreshCount.Add(1)
+		fmt.Fprint(w, tagsToANSI(renderFollow(cfg, content), color))
+		return txCount, true
+	}
+	runRefreshLoop(interval, time.After, done, true, refresh)
+}
@@ -0,0 +1,139 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// A screen which fails to initialize, as when there is no terminal
+type failingScreen struct {
+	tcell.Screen
+	err error
+}
+
+func (s failingScreen) Init() error {
+	return s.err
+}
+
+func TestStartScreen(t *testing.T) {
+	errNoTTY := errors.New("open /dev/tty: no such device or address")
+	tests := []struct {
+		name      string
+		newScreen func() (tcell.Screen, error)
+		fallback  bool
+	}{
+		{
+			"initialized",
+			func() (tcell.Screen, error) {
+				return tcell.NewSimulationScreen(""), nil
+			},
+			false,
+		},
+		{
+			"no terminfo",
+			func() (tcell.Screen, error) {
+				return nil, errors.New("terminal entry not found")
+			},
+			true,
+		},
+		{
+			"init failure",
+			func() (tcell.Screen, error) {
+				return failingScreen{err: errNoTTY}, nil
+			},
+			true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			screen, err := startScreen(test.newScreen)
+			if fallback := err != nil; fallback != test.fallback {
+				t.Fatalf(
+					"got fallback %v (%v), want %v",
+					fallback,
+					err,
+					test.fallback,
+				)
+			}
+			if test.fallback {
+				return
+			}
+			defer screen.Fini()
+			// The application initializes the screen it's given again
+			if err := screen.Init(); err != nil {
+				t.Errorf("got error on second init: %s", err)
+			}
+		})
+	}
+}
+
+func TestRenderFollow(t *testing.T) {
+	cfg := testConfig()
+	got := tagsToANSI(
+		renderFollow(cfg, Content{Main: " [white]Size: [blue]42"}),
+		false,
+	)
+	if !strings.Contains(got, "txtop") {
+		t.Errorf("missing header in %q", got)
+	}
+	if !strings.HasSuffix(got, " Size: 42\n\n") {
+		t.Errorf("got %q, want the content after the header", got)
+	}
+}
+
+// A writer which closes done after the given number of writes
+type countingWriter struct {
+	writes int
+	limit  int
+	done   chan struct{}
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.writes == w.limit {
+		close(w.done)
+	}
+	return len(p), nil
+}
+
+func TestRunFollowCountsRefreshes(t *testing.T) {
+	cfg := testConfig()
+	cfg.App.Demo = true
+	cfg.App.Refresh = minRefresh
+	w := &countingWriter{limit: 3, done: make(chan struct{})}
+	before := refreshCount.Load()
+	finished := make(chan struct{})
+	go func() {
+		runFollow(cfg, w, w.done)
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("follow didn't stop")
+	}
+	if got := refreshCount.Load() - before; got != uint64(w.writes) {
+		t.Errorf("got %d refreshes counted, want %d", got, w.writes)
+	}
+	if w.writes != 3 {
+		t.Errorf("got %d refreshes, want 3", w.writes)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/blinklabs-io/gouroboros/ledger"
+	lcommon "github.com/blinklabs-io/gouroboros/ledger/common"
+)
+
+// Governance kinds we recognize, in the order they're shown in the legend.
+// All but vote are the kinds of action a proposal can make
+var govKinds = []string{
+	"parameter_change",
+	"hard_fork_initiation",
+	"treasury_withdrawal",
+	"no_confidence",
+	"update_committee",
+	"new_constitution",
+	"info",
+	"vote",
+}
+
+// Icons and labels for the governance kinds we recognize, so governance
+// watchers can tell proposals apart from each other and from votes
+var defaultGovLabels = map[string]certLabel{
+	"parameter_change": {
+		Icon:   "🔧",
+		Label:  "Parameter Change",
+		Legend: "Param Change",
+	},
+	"hard_fork_initiation": {
+		Icon:   "🍴",
+		Label:  "Hard Fork Initiation",
+		Legend: "Hard Fork",
+	},
+	"treasury_withdrawal": {
+		Icon:   "💰",
+		Label:  "Treasury Withdrawal",
+		Legend: "Treasury",
+	},
+	"no_confidence": {
+		Icon:   "👎",
+		Label:  "No Confidence",
+		Legend: "No Confidence",
+	},
+	"update_committee": {
+		Icon:   "👥",
+		Label:  "Update Committee",
+		Legend: "Committee",
+	},
+	"new_constitution": {
+		Icon:   "📜",
+		Label:  "New Constitution",
+		Legend: "Constitution",
+	},
+	"info": {
+		Icon:   "💬",
+		Label:  "Info Action",
+		Legend: "Info",
+	},
+	"vote": {
+		Icon:   "🙋",
+		Label:  "Governance Vote",
+		Legend: "Vote",
+	},
+}
+
+// Returns the kind of a governance action as used in defaultGovLabels, or
+// an empty string for actions we don't know
+func govActionKind(action lcommon.GovAction) string {
+	switch action.(type) {
+	case *lcommon.ParameterChangeGovAction:
+		return "parameter_change"
+	case *lcommon.HardForkInitiationGovAction:
+		return "hard_fork_initiation"
+	case *lcommon.TreasuryWithdrawalGovAction:
+		return "treasury_withdrawal"
+	case *lcommon.NoConfidenceGovAction:
+		return "no_confidence"
+	case *lcommon.UpdateCommitteeGovAction:
+		return "update_committee"
+	case *lcommon.NewConstitutionGovAction:
+		return "new_constitution"
+	case *lcommon.InfoGovAction:
+		return "info"
+	}
+	return ""
+}
+
+// Labels transactions by the action of their first recognized proposal,
+// or as a vote when they only cast votes. Eras before Conway have neither
+func govClassifier() Classifier {
+	return ClassifierFunc(func(tx ledger.Transaction) (string, string, bool) {
+		for _, proposal := range tx.ProposalProcedures() {
+			kind := govActionKind(proposal.GovAction.Action)
+			if label, ok := defaultGovLabels[kind]; ok {
+				return label.Label, label.Icon, true
+			}
+		}
+		if len(tx.VotingProcedures()) > 0 {
+			label := defaultGovLabels["vote"]
+			return label.Label, label.Icon, true
+		}
+		return "", "", false
+	})
+}
@@ -0,0 +1,188 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	lcommon "github.com/blinklabs-io/gouroboros/ledger/common"
+)
+
+// Returns a proposal making action
+func testProposal(action lcommon.GovAction) lcommon.ProposalProcedure {
+	return lcommon.ProposalProcedure{
+		GovAction: lcommon.GovActionWrapper{Action: action},
+	}
+}
+
+// Returns votes from a single DRep on a single action
+func testVotes() lcommon.VotingProcedures {
+	return lcommon.VotingProcedures{
+		&lcommon.Voter{Type: lcommon.VoterTypeDRepKeyHash}: {
+			&lcommon.GovActionId{}: lcommon.VotingProcedure{Vote: 1},
+		},
+	}
+}
+
+func TestGovClassifier(t *testing.T) {
+	tests := []struct {
+		name      string
+		tx        fakeTx
+		wantLabel string
+		wantIcon  string
+	}{
+		{name: "no governance"},
+		{
+			name: "parameter change",
+			tx: fakeTx{proposals: []lcommon.ProposalProcedure{
+				testProposal(&lcommon.ParameterChangeGovAction{}),
+			}},
+			wantLabel: "Parameter Change",
+			wantIcon:  "🔧",
+		},
+		{
+			name: "hard fork initiation",
+			tx: fakeTx{proposals: []lcommon.ProposalProcedure{
+				testProposal(&lcommon.HardForkInitiationGovAction{}),
+			}},
+			wantLabel: "Hard Fork Initiation",
+			wantIcon:  "🍴",
+		},
+		{
+			name: "treasury withdrawal",
+			tx: fakeTx{proposals: []lcommon.ProposalProcedure{
+				testProposal(&lcommon.TreasuryWithdrawalGovAction{}),
+			}},
+			wantLabel: "Treasury Withdrawal",
+			wantIcon:  "💰",
+		},
+		{
+			name: "no confidence",
+			tx: fakeTx{proposals: []lcommon.ProposalProcedure{
+				testProposal(&lcommon.NoConfidenceGovAction{}),
+			}},
+			wantLabel: "No Confidence",
+			wantIcon:  "👎",
+		},
+		{
+			name: "update committee",
+			tx: fakeTx{proposals: []lcommon.ProposalProcedure{
+				testProposal(&lcommon.UpdateCommitteeGovAction{}),
+			}},
+			wantLabel: "Update Committee",
+			wantIcon:  "👥",
+		},
+		{
+			name: "new constitution",
+			tx: fakeTx{proposals: []lcommon.ProposalProcedure{
+				testProposal(&lcommon.NewConstitutionGovAction{}),
+			}},
+			wantLabel: "New Constitution",
+			wantIcon:  "📜",
+		},
+		{
+			name: "info",
+			tx: fakeTx{proposals: []lcommon.ProposalProcedure{
+				testProposal(&lcommon.InfoGovAction{}),
+			}},
+			wantLabel: "Info Action",
+			wantIcon:  "💬",
+		},
+		{
+			name:      "vote",
+			tx:        fakeTx{votes: testVotes()},
+			wantLabel: "Governance Vote",
+			wantIcon:  "🙋",
+		},
+		{
+			name: "proposal beats vote",
+			tx: fakeTx{
+				proposals: []lcommon.ProposalProcedure{
+					testProposal(&lcommon.InfoGovAction{}),
+				},
+				votes: testVotes(),
+			},
+			wantLabel: "Info Action",
+			wantIcon:  "💬",
+		},
+		{
+			name: "first recognized proposal",
+			tx: fakeTx{proposals: []lcommon.ProposalProcedure{
+				testProposal(nil),
+				testProposal(&lcommon.TreasuryWithdrawalGovAction{}),
+				testProposal(&lcommon.InfoGovAction{}),
+			}},
+			wantLabel: "Treasury Withdrawal",
+			wantIcon:  "💰",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			label, icon, ok := govClassifier().Classify(test.tx)
+			if ok != (test.wantIcon != "") {
+				t.Fatalf("got ok %t", ok)
+			}
+			if label != test.wantLabel || icon != test.wantIcon {
+				t.Errorf(
+					"got %q %q, want %q %q",
+					label,
+					icon,
+					test.wantLabel,
+					test.wantIcon,
+				)
+			}
+		})
+	}
+}
+
+func TestGovActionKindFromCbor(t *testing.T) {
+	// An info action is encoded as just its type, [6]
+	var wrapper lcommon.GovActionWrapper
+	if err := wrapper.UnmarshalCBOR([]byte{0x81, 0x06}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := govActionKind(wrapper.Action); got != "info" {
+		t.Errorf("got %q, want info", got)
+	}
+}
+
+func TestGovLabelsCoverKinds(t *testing.T) {
+	for _, kind := range govKinds {
+		if _, ok := defaultGovLabels[kind]; !ok {
+			t.Errorf("no label for %s", kind)
+		}
+	}
+	if len(defaultGovLabels) != len(govKinds) {
+		t.Errorf("labels not listed in govKinds")
+	}
+}
+
+func TestClassifyTxGovPrecedence(t *testing.T) {
+	tx := fakeTx{
+		proposals: []lcommon.ProposalProcedure{
+			testProposal(&lcommon.InfoGovAction{}),
+		},
+		outputs: []lcommon.TransactionOutput{
+			testOutput(t, sundaeAddress),
+		},
+	}
+	label, icon := classifyTx(
+		tx,
+		classifiers(nil, knownLabels, nil, 0),
+	)
+	if label != "Info Action" || icon != "💬" {
+		t.Errorf("got %q %q, want the proposal over the address", label, icon)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os/exec"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// How long HEALTH_CMD may run before it counts as failing
+const healthCheckTimeout = 5 * time.Second
+
+type healthState int
+
+const (
+	healthUnknown healthState = iota
+	healthOK
+	healthFailing
+	healthTimeout
+	healthError
+)
+
+// The result of the last health check
+type HealthStatus struct {
+	State healthState
+	// Exit code of a failing check
+	ExitCode int
+}
+
+// Maps the outcome of running the health check to a status. ctxErr is the
+// error from the check's context, which is set when it timed out
+func healthFromResult(err error, ctxErr error) HealthStatus {
+	if errors.Is(ctxErr, context.DeadlineExceeded) {
+		return HealthStatus{State: healthTimeout}
+	}
+	if err == nil {
+		return HealthStatus{State: healthOK}
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return HealthStatus{
+			State:    healthFailing,
+			ExitCode: exitErr.ExitCode(),
+		}
+	}
+	// The command couldn't be started
+	return HealthStatus{State: healthError}
+}
+
+// Describes the status without color tags, for logging
+func (h HealthStatus) describe() string {
+	switch h.State {
+	case healthOK:
+		return "ok"
+	case healthFailing:
+		return fmt.Sprintf("failing (exit %d)", h.ExitCode)
+	case healthTimeout:
+		return "timed out"
+	case healthError:
+		return "could not be run"
+	default:
+		return "unknown"
+	}
+}
+
+func (h HealthStatus) String() string {
+	tag := errorTag()
+	switch h.State {
+	case healthOK:
+		tag = okTag()
+	case healthUnknown:
+		tag = warningTag()
+	}
+	return tag + h.describe() + "[white]"
+}
+
+// Runs command with the shell, giving up after timeout
+func runHealthCheck(command string, timeout time.Duration) HealthStatus {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	args := shellArgs(runtime.GOOS, command)
+	err := exec.CommandContext(ctx, args[0], args[1:]...).Run()
+	return healthFromResult(err, ctx.Err())
+}
+
+// Returns the shell invocation running command on goos: cmd on Windows and
+// sh elsewhere
+func shellArgs(goos string, command string) []string {
+	if goos == "windows" {
+		return []string{"cmd", "/C", command}
+	}
+	return []string{"sh", "-c", command}
+}
+
+var healthMutex sync.Mutex
+var healthStatus HealthStatus
+
+func getHealthStatus() HealthStatus {
+	healthMutex.Lock()
+	defer healthMutex.Unlock()
+	return healthStatus
+}
+
+func setHealthStatus(status HealthStatus) {
+	healthMutex.Lock()
+	defer healthMutex.Unlock()
+	healthStatus = status
+}
+
+// Runs HEALTH_CMD every refresh interval in the background, showing the
+// result in the header
+func startHealthLoop(cfg *Config) {
+	if cfg.App.HealthCmd == "" {
+		return
+	}
+	go func() {
+		for {
+			status := runHealthCheck(cfg.App.HealthCmd, healthCheckTimeout)
+			if status.State != healthOK {
+				log.Printf("health check %s", status.describe())
+			}
+			setHealthStatus(status)
+			header := renderHeader(cfg)
+			redraw.Request("header", func() {
+				headerText.SetText(header)
+			})
+			// Commands are slower to run than the node is to query
+			time.Sleep(max(cfg.App.Refresh.Duration(), time.Second))
+		}
+	}()
+}
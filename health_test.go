@@ -0,0 +1,95 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRunHealthCheck(t *testing.T) {
+	tests := []struct {
+		command  string
+		timeout  time.Duration
+		want     healthState
+		exitCode int
+	}{
+		{"true", time.Second, healthOK, 0},
+		{"exit 3", time.Second, healthFailing, 3},
+		{"sleep 5", 50 * time.Millisecond, healthTimeout, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.command, func(t *testing.T) {
+			status := runHealthCheck(test.command, test.timeout)
+			if status.State != test.want || status.ExitCode != test.exitCode {
+				t.Errorf("got %+v", status)
+			}
+		})
+	}
+}
+
+func TestShellArgs(t *testing.T) {
+	tests := []struct {
+		goos string
+		want []string
+	}{
+		{"linux", []string{"sh", "-c", "exit 3"}},
+		{"darwin", []string{"sh", "-c", "exit 3"}},
+		{"windows", []string{"cmd", "/C", "exit 3"}},
+	}
+	for _, test := range tests {
+		t.Run(test.goos, func(t *testing.T) {
+			got := shellArgs(test.goos, "exit 3")
+			if !slices.Equal(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestHealthFromResult(t *testing.T) {
+	status := healthFromResult(
+		errors.New("exec: not found"),
+		nil,
+	)
+	if status.State != healthError {
+		t.Errorf("got %+v for a command that couldn't start", status)
+	}
+	status = healthFromResult(nil, context.DeadlineExceeded)
+	if status.State != healthTimeout {
+		t.Errorf("got %+v for a timeout", status)
+	}
+}
+
+func TestHealthStatusDescribe(t *testing.T) {
+	tests := []struct {
+		status HealthStatus
+		want   string
+	}{
+		{HealthStatus{}, "unknown"},
+		{HealthStatus{State: healthOK}, "ok"},
+		{HealthStatus{State: healthFailing, ExitCode: 2}, "failing (exit 2)"},
+		{HealthStatus{State: healthTimeout}, "timed out"},
+		{HealthStatus{State: healthError}, "could not be run"},
+	}
+	for _, test := range tests {
+		if got := test.status.describe(); got != test.want {
+			t.Errorf("got %q, want %q", got, test.want)
+		}
+	}
+}
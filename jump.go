@@ -0,0 +1,119 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Returns the index of the first record whose hash starts with prefix, or
+// -1 when none match. Matching ignores case
+func findByHashPrefix(records []TxRecord, prefix string) int {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" {
+		return -1
+	}
+	for i, record := range records {
+		if strings.HasPrefix(strings.ToLower(record.Hash), prefix) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Opens a prompt for a hash prefix, then scrolls the transactions to the
+// first match
+func showJumpPrompt(cfg *Config) {
+	input := tview.NewInputField().
+		SetLabel(" Go to hash: ").
+		SetFieldWidth(64)
+	input.SetDoneFunc(func(key tcell.Key) {
+		pages.RemovePage("Jump")
+		if key == tcell.KeyEnter {
+			jumpToHashPrefix(cfg, input.GetText())
+		}
+	})
+	prompt := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(input, 1, 0, true)
+	pages.AddPage("Jump", prompt, true, true)
+}
+
+// Scrolls the transactions so the first one matching prefix is at the top.
+// With KEEP_SELECTION it's also selected, so it stays highlighted and in
+// view across refreshes, until an empty prefix clears the selection. Runs
+// on the tview event loop
+func jumpToHashPrefix(cfg *Config, prefix string) {
+	if cfg.App.KeepSelection && strings.TrimSpace(prefix) == "" {
+		setSelection(selection{})
+		rerenderFromCache(cfg)
+		return
+	}
+	snapshot := getLastSnapshot()
+	records := displayedTransactions(
+		cfg,
+		filterTransactions(snapshot.Records, getFilterState().filters()),
+	)
+	index := findByHashPrefix(records, prefix)
+	if index < 0 {
+		return
+	}
+	if cfg.App.KeepSelection {
+		setSelection(selection{Hash: records[index].Hash, Index: index})
+		rerenderFromCache(cfg)
+	}
+	row := lineContaining(
+		displayed.Main,
+		shownHash(cfg, records[index].Hash),
+		displayed.Table,
+	)
+	if row >= 0 {
+		text.ScrollTo(row, 0)
+	}
+}
+
+// Returns the part of the hash that's always shown in the list, which is
+// shortened in compact mode and may be cut short on narrow terminals
+func shownHash(cfg *Config, hash string) string {
+	if cfg.App.Compact && len(hash) > compactHashLength {
+		return hash[:compactHashLength]
+	}
+	if len(hash) > minHashWidth-1 {
+		return hash[:minHashWidth-1]
+	}
+	return hash
+}
+
+// Lines of a text from First up to, but not including, End
+type lineRange struct {
+	First int
+	End   int
+}
+
+// Returns the first line of text within lines containing needle, or -1
+// when none do
+func lineContaining(text string, needle string, lines lineRange) int {
+	for row, line := range strings.Split(text, "\n") {
+		if row >= lines.First && row < lines.End &&
+			strings.Contains(line, needle) {
+			return row
+		}
+	}
+	return -1
+}
@@ -0,0 +1,103 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindByHashPrefix(t *testing.T) {
+	records := []TxRecord{{Hash: "abcd"}, {Hash: "abef"}, {Hash: "ffff"}}
+	tests := []struct {
+		prefix string
+		want   int
+	}{
+		{"ab", 0},
+		{"ABE", 1},
+		{" ff ", 2},
+		{"00", -1},
+		{"", -1},
+	}
+	for _, test := range tests {
+		if got := findByHashPrefix(records, test.prefix); got != test.want {
+			t.Errorf("%q: got %d, want %d", test.prefix, got, test.want)
+		}
+	}
+}
+
+func TestJumpFindsCompactCells(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	records := []TxRecord{
+		{Hash: strings.Repeat("0", 64)},
+		{Hash: strings.Repeat("1", 64)},
+		{Hash: hash},
+	}
+	tests := []struct {
+		name    string
+		compact bool
+		text    string
+	}{
+		{
+			name: "table",
+			text: FormatTransactions(&Config{}, records),
+		},
+		{
+			name:    "compact",
+			compact: true,
+			text:    formatCompactGrid(records, nil, 30, nil),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.App.Compact = test.compact
+			row := lineContaining(
+				test.text,
+				shownHash(cfg, hash),
+				lineRange{End: strings.Count(test.text, "\n")},
+			)
+			if row < 0 {
+				t.Fatalf("hash not found in %q", test.text)
+			}
+			line := strings.Split(test.text, "\n")[row]
+			if !strings.Contains(line, hash[:compactHashLength]) {
+				t.Errorf("found the wrong line %q", line)
+			}
+		})
+	}
+}
+
+func TestJumpSkipsLargest(t *testing.T) {
+	defer setLastSnapshot(getLastSnapshot())
+	defer func(saved Content) { displayed = saved }(displayed)
+	snapshot, err := GetDemoSnapshot()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	setLastSnapshot(snapshot)
+	cfg := testConfig()
+	cfg.App.ShowLargest = true
+	displayed = Content{}
+	rerenderFromCache(cfg)
+	largest, _ := largestTransaction(snapshot.Records)
+	jumpToHashPrefix(cfg, largest.Hash[:8])
+	row, _ := text.GetScrollOffset()
+	line := strings.Split(displayed.Main, "\n")[row]
+	if !strings.Contains(line, shownHash(cfg, largest.Hash)) ||
+		strings.Contains(line, "Largest:") {
+		t.Errorf("jumped to %q, want the transaction's row", line)
+	}
+}
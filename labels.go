@@ -0,0 +1,358 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// What a known label is matched against
+const (
+	matchAddress      = "address"
+	matchStakeAddress = "stake_address"
+	matchMessage      = "message"
+)
+
+// Valid match types for LABELS_FILE entries
+var labelMatchTypes = []string{
+	matchAddress,
+	matchStakeAddress,
+	matchMessage,
+}
+
+// Maps a payment address, a stake address, or the first line of a CIP-20
+// message to an icon. Name and Category give icons which aren't already in
+// the legend an entry there
+type knownLabel struct {
+	Match    string `json:"match"`
+	Value    string `json:"value"`
+	Icon     string `json:"icon"`
+	Name     string `json:"name"`
+	Category string `json:"category,omitempty"`
+}
+
+// Known labels indexed by match type and value
+type labelTable map[string]map[string]knownLabel
+
+// Indexes the given lists of labels, with entries in later lists replacing
+// those in earlier ones which match the same value
+func newLabelTable(lists ...[]knownLabel) labelTable {
+	table := make(labelTable, len(labelMatchTypes))
+	for _, labels := range lists {
+		for _, label := range labels {
+			if table[label.Match] == nil {
+				table[label.Match] = make(map[string]knownLabel)
+			}
+			table[label.Match][label.Value] = label
+		}
+	}
+	return table
+}
+
+// Returns the icon for value, or nothing when it's not known
+func (t labelTable) icon(match string, value string) string {
+	return t[match][value].Icon
+}
+
+// Built in labels for well known protocols
+var defaultKnownLabels = []knownLabel{
+	// Axo
+	{
+		Match: matchAddress,
+		Value: "addr1w8ytzffgwpf94dy20kgw72gn9ujjhqu3md34vhggenkakeszhjpl3",
+		Icon:  "❌",
+		Name:  "Axo",
+	},
+	{
+		Match: matchAddress,
+		Value: "addr1z8ytzffgwpf94dy20kgw72gn9ujjhqu3md34vhggenkakejv7ncp3yppt0gcr50u60y43x32fgadhnl35u9hfqyql2pqr3p0j4",
+		Icon:  "❌",
+		Name:  "Axo",
+	},
+	// Dripdropz
+	{
+		Match: matchAddress,
+		Value: "addr1v8pr9mwnqarw808gtllvmlxvk70hnszrukjeqfstr9t9g5crud8c4",
+		Icon:  "🚰",
+		Name:  "DripDropz",
+	},
+	// Indigo, with a space because the icon is only 1 char wide
+	{
+		Match: matchAddress,
+		Value: "addr1w80ptp0qgmcklhmeweesqgeurtlma8fsxsr9dt8au30fzss0czhl9",
+		Icon:  "👁️ ",
+		Name:  "Indigo",
+	},
+	{
+		Match: matchAddress,
+		Value: "addr1w92w34pys9h4h02zxdfsp8lhcvdd5t9aaln9z96szsgh73scty4aj",
+		Icon:  "👁️ ",
+		Name:  "Indigo",
+	},
+	{
+		Match: matchAddress,
+		Value: "addr1w8q673nyx6vtcules4aqess7e9yuu6geja95xhg90hzy3wqpsjzzz",
+		Icon:  "👁️ ",
+		Name:  "Indigo",
+	},
+	{
+		Match: matchAddress,
+		Value: "addr1wxj88juwkzmpcqacd9hua2cur2yl50kgx3tjs588c2470qc2ftfae",
+		Icon:  "👁️ ",
+		Name:  "Indigo",
+	},
+	// JPG
+	{
+		Match: matchAddress,
+		Value: "addr1zxgx3far7qygq0k6epa0zcvcvrevmn0ypsnfsue94nsn3tvpw288a4x0xf8pxgcntelxmyclq83s0ykeehchz2wtspks905plm",
+		Icon:  "🦛",
+		Name:  "JPGstore",
+	},
+	// Liqwid
+	{
+		Match: matchAddress,
+		Value: "addr1wx6htk5hfmr4dw32lhxdcp7t6xpe4jhs5fxylq90mqwnldsvr87c6",
+		Icon:  "💧",
+		Name:  "Liqwid",
+	},
+	{
+		Match: matchAddress,
+		Value: "addr1wyn2aflq8ff7xaxpmqk9vz53ks28hz256tkyaj739rsvrrq3u5ft3",
+		Icon:  "💧",
+		Name:  "Liqwid",
+	},
+	{
+		Match: matchAddress,
+		Value: "addr1w8arvq7j9qlrmt0wpdvpp7h4jr4fmfk8l653p9t907v2nsss7w7r4",
+		Icon:  "💧",
+		Name:  "Liqwid",
+	},
+	// Minswap
+	{
+		Match: matchAddress,
+		Value: "addr1z84q0denmyep98ph3tmzwsmw0j7zau9ljmsqx6a4rvaau66j2c79gy9l76sdg0xwhd7r0c0kna0tycz4y5s6mlenh8pq777e2a",
+		Icon:  "🐱",
+		Name:  "Minswap",
+	},
+	// Optim
+	{
+		Match: matchAddress,
+		Value: "addr1zywj8y96k38kye7qz329dhp0t782ykr0ev92mtz4yhv6gph8ucsr8rpyzewcf9jyf7gmjj052dednasdeznehw7aqc7q0z7vn2",
+		Icon:  "🅾️",
+		Name:  "Optim",
+	},
+	// Silk Toad
+	{
+		Match: matchAddress,
+		Value: "addr1w9d85mfr73mk8pr5erd46d7e7whcah2tzcyqd5rr4hv2amg9sxgl8",
+		Icon:  "🕺",
+		Name:  "Silk Toad",
+	},
+	{
+		Match: matchAddress,
+		Value: "addr1xxj62lufz8se8rlr7r79ap7rwa845f4gnvm6qls85kuxpw9954lcjy0pjw878u8ut6ruxa60tgn23xeh5plq0fdcvzuq7kuswe",
+		Icon:  "🕺",
+		Name:  "Silk Toad",
+	},
+	// Spectrum
+	{
+		Match: matchAddress,
+		Value: "addr1wyr4uz0tp75fu8wrg6gm83t20aphuc9vt6n8kvu09ctkugqpsrmeh",
+		Icon:  "🌈",
+		Name:  "Spectrum",
+	},
+	{
+		Match: matchAddress,
+		Value: "addr1x94ec3t25egvhqy2n265xfhq882jxhkknurfe9ny4rl9k6dj764lvrxdayh2ux30fl0ktuh27csgmpevdu89jlxppvrst84slu",
+		Icon:  "🌈",
+		Name:  "Spectrum",
+	},
+	{
+		Match: matchAddress,
+		Value: "addr1x8nz307k3sr60gu0e47cmajssy4fmld7u493a4xztjrll0aj764lvrxdayh2ux30fl0ktuh27csgmpevdu89jlxppvrswgxsta",
+		Icon:  "🌈",
+		Name:  "Spectrum",
+	},
+	{
+		Match: matchAddress,
+		Value: "addr1wynp362vmvr8jtc946d3a3utqgclfdl5y9d3kn849e359hsskr20n",
+		Icon:  "🌈",
+		Name:  "Spectrum",
+	},
+	// Sundae
+	{
+		Match: matchAddress,
+		Value: "addr1wxaptpmxcxawvr3pzlhgnpmzz3ql43n2tc8mn3av5kx0yzs09tqh8",
+		Icon:  "🍨",
+		Name:  "Sundae",
+	},
+	{
+		Match: matchAddress,
+		Value: "addr1w9qzpelu9hn45pefc0xr4ac4kdxeswq7pndul2vuj59u8tqaxdznu",
+		Icon:  "🍨",
+		Name:  "Sundae",
+	},
+	{
+		Match: matchAddress,
+		Value: "addr1w9jx45flh83z6wuqypyash54mszwmdj8r64fydafxtfc6jgrw4rm3",
+		Icon:  "🍨",
+		Name:  "Sundae",
+	},
+	{
+		Match: matchAddress,
+		Value: "addr1x8srqftqemf0mjlukfszd97ljuxdp44r372txfcr75wrz26rnxqnmtv3hdu2t6chcfhl2zzjh36a87nmd6dwsu3jenqsslnz7e",
+		Icon:  "🍨",
+		Name:  "Sundae",
+	},
+	{
+		Match: matchAddress,
+		Value: "addr1z8ax5k9mutg07p2ngscu3chsauktmstq92z9de938j8nqal9r9z8yaghysf05atjyv79t73lercjdqnejetxm307m49qdfqcxd",
+		Icon:  "🍨",
+		Name:  "Sundae",
+	},
+	// VyFinance
+	{
+		Match: matchAddress,
+		Value: "addr1w8ll74xa05dkn69n3rmp93h8maphmms2408nt0nyruarzvqr9zf64",
+		Icon:  "🔵",
+		Name:  "VyFinance",
+	},
+	{
+		Match: matchAddress,
+		Value: "addr1z976yepnveus5uddth7qd66kn6cuzd7tccjd39dfdayc7lnend0q3h5twed567pu236a0sf6vfgruxgpr4rkxryyx0zqa550y7",
+		Icon:  "🔵",
+		Name:  "VyFinance",
+	},
+	// Wingriders
+	{
+		Match: matchAddress,
+		Value: "addr1wxr2a8htmzuhj39y2gq7ftkpxv98y2g67tg8zezthgq4jkg0a4ul4",
+		Icon:  "🦸",
+		Name:  "Wingriders",
+	},
+	// Seal's Vending Machine
+	{
+		Match: matchStakeAddress,
+		Value: "stake1u8ffzkegp8h48mare3g3ntf3xmjce3jqptsdtj38ee3yh3c9t4uum",
+		Icon:  "🦭",
+		Name:  "SealVM",
+	},
+	// Dexhunter
+	{Match: matchMessage, Value: "Dexhunter Trade", Icon: "🏹", Name: "Dexhunter"},
+	// Minswap
+	{Match: matchMessage, Value: "Minswap: Deposit Order", Icon: "🐱", Name: "Minswap"},
+	{Match: matchMessage, Value: "Minswap: Cancel Order", Icon: "🐱", Name: "Minswap"},
+	{Match: matchMessage, Value: "Minswap: Create Pool", Icon: "🐱", Name: "Minswap"},
+	{Match: matchMessage, Value: "Minswap: Launch Bowl Redemption", Icon: "🐱", Name: "Minswap"},
+	{Match: matchMessage, Value: "Minswap: LBE Deposit ADA", Icon: "🐱", Name: "Minswap"},
+	{Match: matchMessage, Value: "Minswap: Liquidity Migration", Icon: "🐱", Name: "Minswap"},
+	{Match: matchMessage, Value: "Minswap: MasterChef", Icon: "🐱", Name: "Minswap"},
+	{Match: matchMessage, Value: "Minswap: Order Executed", Icon: "🐱", Name: "Minswap"},
+	{Match: matchMessage, Value: "Minswap: Swap Exact In Order", Icon: "🐱", Name: "Minswap"},
+	{Match: matchMessage, Value: "Minswap: Swap Exact In Limit Order", Icon: "🐱", Name: "Minswap"},
+	{Match: matchMessage, Value: "Minswap: Swap Exact Out Order", Icon: "🐱", Name: "Minswap"},
+	{Match: matchMessage, Value: "Minswap: Swap Exact Out Limit Order", Icon: "🐱", Name: "Minswap"},
+	{Match: matchMessage, Value: "Minswap: V2 Harvest reward", Icon: "🐱", Name: "Minswap"},
+	{Match: matchMessage, Value: "Minswap: V2 Stake liquidity", Icon: "🐱", Name: "Minswap"},
+	{Match: matchMessage, Value: "Minswap: Withdraw Order", Icon: "🐱", Name: "Minswap"},
+	{Match: matchMessage, Value: "Minswap: Zap Order", Icon: "🐱", Name: "Minswap"},
+	// Sundae
+	{Match: matchMessage, Value: "SSP: Swap Request", Icon: "🍨", Name: "Sundae"},
+}
+
+// Labels from LABELS_FILE, loaded in main
+var userLabels []knownLabel
+
+// The built in labels with those from LABELS_FILE applied, replaced in
+// main once they're loaded
+var knownLabels = newLabelTable(defaultKnownLabels)
+
+// Reads a JSON list of labels from path. Entries matching the same value
+// as a built in label replace it, while the rest are added
+func LoadLabels(path string) ([]knownLabel, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var labels []knownLabel
+	if err := json.Unmarshal(data, &labels); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for i, label := range labels {
+		label.Match = strings.ToLower(strings.TrimSpace(label.Match))
+		label.Value = strings.TrimSpace(label.Value)
+		label.Category = strings.ToLower(strings.TrimSpace(label.Category))
+		if label.Value == "" || label.Icon == "" || label.Name == "" {
+			return nil, fmt.Errorf(
+				"label %d in %s: value, icon and name are required",
+				i,
+				path,
+			)
+		}
+		if !slices.Contains(labelMatchTypes, label.Match) {
+			return nil, fmt.Errorf(
+				"label %q in %s: invalid match %q (expected one of: %s)",
+				label.Name,
+				path,
+				label.Match,
+				strings.Join(labelMatchTypes, ", "),
+			)
+		}
+		if label.Category == "" {
+			label.Category = "defi"
+		}
+		if !slices.Contains(legendCategories, label.Category) {
+			return nil, fmt.Errorf(
+				"label %q in %s: invalid category %q (expected one of: %s)",
+				label.Name,
+				path,
+				label.Category,
+				strings.Join(legendCategories, ", "),
+			)
+		}
+		labels[i] = label
+	}
+	return labels, nil
+}
+
+// Builds a legend entry for each icon in labels which isn't already in
+// entries
+func labelLegendEntries(
+	labels []knownLabel,
+	entries []legendEntry,
+) []legendEntry {
+	icons := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		icons = append(icons, entry.Icon)
+	}
+	var added []legendEntry
+	for _, label := range labels {
+		// Some icons are padded with a space to fill two cells
+		icon := strings.TrimSpace(label.Icon)
+		if slices.Contains(icons, icon) {
+			continue
+		}
+		icons = append(icons, icon)
+		added = append(
+			added,
+			legendEntry{Icon: icon, Name: label.Name, Category: label.Category},
+		)
+	}
+	return added
+}
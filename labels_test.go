@@ -0,0 +1,132 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	lcommon "github.com/blinklabs-io/gouroboros/ledger/common"
+)
+
+func writeLabelsFile(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "labels.json")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadLabels(t *testing.T) {
+	path := writeLabelsFile(t, `[
+		{"match": " Address ", "value": "`+sundaeAddress+`", "icon": "🦄", "name": "Unidex"},
+		{"match": "message", "value": "Unidex: Swap", "icon": "🦄", "name": "Unidex", "category": "NFT"}
+	]`)
+	labels, err := LoadLabels(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(labels) != 2 {
+		t.Fatalf("got %d labels, want 2", len(labels))
+	}
+	if labels[0].Match != matchAddress || labels[0].Category != "defi" {
+		t.Errorf("match or default category not normalized: %+v", labels[0])
+	}
+	if labels[1].Category != "nft" {
+		t.Errorf("category not normalized: %+v", labels[1])
+	}
+}
+
+func TestLoadLabelsInvalid(t *testing.T) {
+	for _, data := range []string{
+		`[{"match": "policy", "value": "abc", "icon": "🦄", "name": "Unidex"}]`,
+		`[{"match": "address", "value": "", "icon": "🦄", "name": "Unidex"}]`,
+		`[{"match": "address", "value": "addr1", "icon": "", "name": "Unidex"}]`,
+		`[{"match": "address", "value": "addr1", "icon": "🦄", "name": "Unidex", "category": "games"}]`,
+		`{"match": "address"}`,
+	} {
+		if _, err := LoadLabels(writeLabelsFile(t, data)); err == nil {
+			t.Errorf("%s: expected an error", data)
+		}
+	}
+}
+
+func TestLabelTableOverrides(t *testing.T) {
+	table := newLabelTable(defaultKnownLabels, []knownLabel{
+		{Match: matchAddress, Value: sundaeAddress, Icon: "🦄"},
+		{Match: matchMessage, Value: "Unidex: Swap", Icon: "🦄"},
+	})
+	tests := []struct {
+		match string
+		value string
+		want  string
+	}{
+		{matchAddress, sundaeAddress, "🦄"},
+		{matchAddress, minswapAddress, "🐱"},
+		{matchMessage, "Unidex: Swap", "🦄"},
+		{matchMessage, "SSP: Swap Request", "🍨"},
+		{matchStakeAddress, sealStake, "🦭"},
+		{matchStakeAddress, sundaeAddress, ""},
+	}
+	for _, test := range tests {
+		if got := table.icon(test.match, test.value); got != test.want {
+			t.Errorf(
+				"%s %s: got %q, want %q",
+				test.match,
+				test.value,
+				got,
+				test.want,
+			)
+		}
+	}
+}
+
+func TestClassifyWithUserLabels(t *testing.T) {
+	table := newLabelTable(defaultKnownLabels, []knownLabel{
+		{Match: matchAddress, Value: sundaeAddress, Icon: "🦄"},
+	})
+	tx := fakeTx{
+		outputs: []lcommon.TransactionOutput{
+			testOutput(t, sundaeAddress),
+		},
+	}
+	if _, icon := classifyTx(tx, classifiers(nil, table, nil, 0)); icon != "🦄" {
+		t.Errorf("got %q, want the overridden icon", icon)
+	}
+}
+
+func TestLabelLegendEntries(t *testing.T) {
+	labels := []knownLabel{
+		{Icon: "🍨", Name: "Sundae V3", Category: "defi"},
+		{Icon: "🦄", Name: "Unidex", Category: "defi"},
+		{Icon: "🦄", Name: "Unidex", Category: "defi"},
+		{Icon: "🎲 ", Name: "Dice", Category: "services"},
+	}
+	added := labelLegendEntries(labels, defaultLegendEntries)
+	want := []legendEntry{
+		{Icon: "🦄", Name: "Unidex", Category: "defi"},
+		{Icon: "🎲", Name: "Dice", Category: "services"},
+	}
+	if len(added) != len(want) {
+		t.Fatalf("got %+v, want %+v", added, want)
+	}
+	for i := range want {
+		if added[i] != want[i] {
+			t.Errorf("entry %d: got %+v, want %+v", i, added[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+)
+
+// Returns the largest transaction, breaking ties by the lowest hash so the
+// choice doesn't depend on the order the node returned them in. Reports
+// false when there are no transactions
+func largestTransaction(records []TxRecord) (TxRecord, bool) {
+	if len(records) == 0 {
+		return TxRecord{}, false
+	}
+	largest := records[0]
+	for _, record := range records[1:] {
+		if record.Size > largest.Size ||
+			(record.Size == largest.Size && record.Hash < largest.Hash) {
+			largest = record
+		}
+	}
+	return largest, true
+}
+
+// Returns the name shown for a transaction's label
+func displayLabel(entries []legendEntry, record TxRecord) string {
+	if label := resolvedLabel(entries, record); label != "" {
+		return label
+	}
+	return "unlabeled"
+}
+
+// Formats a line for the largest transaction, regardless of the sort and
+// filters, or nothing when the mempool is empty
+func FormatLargest(cfg *Config, records []TxRecord) string {
+	largest, ok := largestTransaction(records)
+	if !ok {
+		return ""
+	}
+	if cfg.App.RedactHashes {
+		largest.Hash = redactHash(largest.Hash, redactKey)
+	}
+	return fmt.Sprintf(
+		" [white]Largest: [blue]%d[white] bytes %s %s [blue]%s[white]\n",
+		largest.Size,
+		largest.Icon,
+		displayLabel(allLegendEntries(cfg), largest),
+		largest.Hash,
+	)
+}
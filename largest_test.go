@@ -0,0 +1,82 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+)
+
+func TestLargestTransaction(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []TxRecord
+		want    string
+		wantOK  bool
+	}{
+		{"empty", nil, "", false},
+		{
+			"largest",
+			[]TxRecord{
+				{Hash: "a", Size: 100},
+				{Hash: "b", Size: 300},
+				{Hash: "c", Size: 200},
+			},
+			"b",
+			true,
+		},
+		{
+			"tie broken by hash",
+			[]TxRecord{
+				{Hash: "d", Size: 300},
+				{Hash: "b", Size: 300},
+				{Hash: "c", Size: 300},
+			},
+			"b",
+			true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := largestTransaction(test.records)
+			if ok != test.wantOK || got.Hash != test.want {
+				t.Errorf(
+					"got %q, %t, want %q, %t",
+					got.Hash,
+					ok,
+					test.want,
+					test.wantOK,
+				)
+			}
+		})
+	}
+}
+
+func TestDisplayLabel(t *testing.T) {
+	entries := []legendEntry{{Icon: "🐱", Name: "Minswap"}}
+	tests := []struct {
+		record TxRecord
+		want   string
+	}{
+		{TxRecord{Label: "Stake delegation", Icon: "🥩"}, "Stake delegation"},
+		{TxRecord{Icon: "🐱"}, "Minswap"},
+		{TxRecord{Icon: "🦄"}, "🦄"},
+		{TxRecord{}, "unlabeled"},
+	}
+	for _, test := range tests {
+		if got := displayLabel(entries, test.record); got != test.want {
+			t.Errorf("%+v: got %q, want %q", test.record, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// Legend width when the terminal size is unknown
+const defaultLegendWidth = 80
+
+// Printed before the first row of the legend, and as indentation before the
+// rest
+const legendPrefix = " Legend:"
+
+// An icon shown in the legend and the category it belongs to
+type legendEntry struct {
+	Icon     string
+	Name     string
+	Category string
+}
+
+// Valid values for LegendCategories
+var legendCategories = []string{
+	"defi",
+	"governance",
+	"nft",
+	"services",
+	"staking",
+	"wallets",
+}
+
+// Colors for each category, shared by the legend and the category counts so
+// they read the same. Labeled transactions without a legend entry are
+// counted as other
+var categoryColors = map[string]string{
+	"defi":       "aqua",
+	"governance": "yellow",
+	"nft":        "fuchsia",
+	"services":   "orange",
+	"staking":    "lime",
+	"wallets":    "teal",
+	"other":      "gray",
+}
+
+// Wraps text in the color tag for a category
+func colorCategory(category string, text string) string {
+	color, ok := categoryColors[category]
+	if !ok {
+		return text
+	}
+	return "[" + color + "]" + text + "[white]"
+}
+
+var defaultLegendEntries = []legendEntry{
+	{Icon: "🏹", Name: "Dexhunter", Category: "defi"},
+	{Icon: "🚰", Name: "DripDropz", Category: "services"},
+	{Icon: "👁️", Name: "Indigo", Category: "defi"},
+	{Icon: "🦛", Name: "JPGstore", Category: "nft"},
+	{Icon: "💧", Name: "Liqwid", Category: "defi"},
+	{Icon: "🐱", Name: "Minswap", Category: "defi"},
+	{Icon: "🅾️", Name: "Optim", Category: "defi"},
+	{Icon: "🌈", Name: "Spectrum", Category: "defi"},
+	{Icon: "🍨", Name: "Sundae", Category: "defi"},
+	{Icon: "🦭", Name: "SealVM", Category: "services"},
+	{Icon: "🦸", Name: "Wingriders", Category: "defi"},
+}
+
+// Builds a legend entry per certificate kind, with its overridden icon if
+// any
+func certLegendEntries(iconOverrides map[string]string) []legendEntry {
+	entries := make([]legendEntry, 0, len(certKinds))
+	for _, kind := range certKinds {
+		label, _ := classifyCertificateKind(kind, iconOverrides)
+		entries = append(
+			entries,
+			legendEntry{
+				Icon:     label.Icon,
+				Name:     label.Legend,
+				Category: "staking",
+			},
+		)
+	}
+	return entries
+}
+
+// Builds a legend entry per governance kind
+func govLegendEntries() []legendEntry {
+	entries := make([]legendEntry, 0, len(govKinds))
+	for _, kind := range govKinds {
+		label := defaultGovLabels[kind]
+		entries = append(
+			entries,
+			legendEntry{
+				Icon:     label.Icon,
+				Name:     label.Legend,
+				Category: "governance",
+			},
+		)
+	}
+	return entries
+}
+
+// Returns every legend entry, regardless of the configured categories
+func allLegendEntries(cfg *Config) []legendEntry {
+	entries := append(
+		slices.Clone(defaultLegendEntries),
+		certLegendEntries(cfg.App.CertIcons)...,
+	)
+	entries = append(entries, govLegendEntries()...)
+	entries = append(entries, labelLegendEntries(userLabels, entries)...)
+	return append(entries, walletLegendEntries(knownWallets)...)
+}
+
+// Returns the legend entries for the configured categories, or all of them
+// when no categories are configured
+func legendEntries(cfg *Config) []legendEntry {
+	entries := allLegendEntries(cfg)
+	if len(cfg.App.LegendCategories) == 0 {
+		return entries
+	}
+	var shown []legendEntry
+	for _, entry := range entries {
+		if slices.Contains(cfg.App.LegendCategories, entry.Category) {
+			shown = append(shown, entry)
+		}
+	}
+	return shown
+}
+
+// Lays out legend entries in rows of equal width columns, as many as fit
+// in width cells, returning the text and the number of rows
+func renderLegend(entries []legendEntry, width int) (string, int) {
+	if len(entries) == 0 {
+		return "", 0
+	}
+	if width <= 0 {
+		width = defaultLegendWidth
+	}
+	var columnWidth int
+	labels := make([]string, len(entries))
+	for i, entry := range entries {
+		labels[i] = entry.Icon + " " + colorCategory(entry.Category, entry.Name)
+		columnWidth = max(columnWidth, tview.TaggedStringWidth(labels[i]))
+	}
+	perRow := legendPerRow(columnWidth, width)
+	var sb strings.Builder
+	var rows int
+	for start := 0; start < len(labels); start += perRow {
+		if rows == 0 {
+			sb.WriteString(legendPrefix + "[white]")
+		} else {
+			sb.WriteString("\n" + strings.Repeat(" ", len(legendPrefix)))
+		}
+		end := min(start+perRow, len(labels))
+		for i, label := range labels[start:end] {
+			sb.WriteString(" " + label)
+			if start+i < end-1 {
+				padding := columnWidth - tview.TaggedStringWidth(label)
+				sb.WriteString(strings.Repeat(" ", padding))
+			}
+		}
+		rows++
+	}
+	return sb.String(), rows
+}
+
+// Returns how many columns of columnWidth cells, each after a space, fit
+// beside the prefix in width cells. There's always at least one, so an
+// entry wider than the terminal gets a row to itself rather than being cut
+func legendPerRow(columnWidth int, width int) int {
+	return max(1, (width-len(legendPrefix))/(columnWidth+1))
+}
+
+func validateLegendCategories(categories []string) error {
+	for i, category := range categories {
+		category = strings.ToLower(strings.TrimSpace(category))
+		if !slices.Contains(legendCategories, category) {
+			return fmt.Errorf(
+				"invalid LEGEND_CATEGORIES entry: %q (expected any of: %s)",
+				category,
+				strings.Join(legendCategories, ", "),
+			)
+		}
+		categories[i] = category
+	}
+	return nil
+}
+
+// Returns a transaction's label, or the legend name of its icon when it
+// only has an icon, or its icon when that isn't in the legend either. Empty
+// for unlabeled transactions
+func resolvedLabel(entries []legendEntry, record TxRecord) string {
+	if record.Label != "" {
+		return record.Label
+	}
+	// Some icons are padded with a space to fill two cells
+	icon := strings.TrimSpace(record.Icon)
+	for _, entry := range entries {
+		if entry.Icon == icon {
+			return entry.Name
+		}
+	}
+	return icon
+}
+
+// Counts labeled transactions by the category of their icon's legend entry
+func categoryCounts(
+	entries []legendEntry,
+	records []TxRecord,
+) map[string]int {
+	categories := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		categories[entry.Icon] = entry.Category
+	}
+	counts := make(map[string]int)
+	for _, record := range records {
+		if record.Icon == "" {
+			continue
+		}
+		// Some icons are padded with a space to fill two cells
+		category, ok := categories[strings.TrimSpace(record.Icon)]
+		if !ok {
+			category = "other"
+		}
+		counts[category]++
+	}
+	return counts
+}
+
+// Formats the category counts on one line, naming each category in its
+// legend color
+func FormatCategoryCounts(counts map[string]int) string {
+	var sb strings.Builder
+	for _, category := range append(slices.Clone(legendCategories), "other") {
+		if counts[category] == 0 {
+			continue
+		}
+		sb.WriteString(
+			fmt.Sprintf(
+				" %s [blue]%d[white]",
+				colorCategory(category, category),
+				counts[category],
+			),
+		)
+	}
+	if sb.Len() == 0 {
+		return ""
+	}
+	return " [white]Categories:" + sb.String() + "\n"
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rivo/tview"
+)
+
+func TestCategoryCounts(t *testing.T) {
+	counts := categoryCounts(
+		allLegendEntries(&Config{}),
+		[]TxRecord{
+			{Icon: "🐱"},
+			{Icon: "👁️ "},
+			{Icon: "🦛"},
+			{Icon: "🧪"},
+			{},
+		},
+	)
+	want := map[string]int{"defi": 2, "nft": 1, "other": 1}
+	if len(counts) != len(want) {
+		t.Fatalf("got %v, want %v", counts, want)
+	}
+	for category, count := range want {
+		if counts[category] != count {
+			t.Errorf("%s: got %d, want %d", category, counts[category], count)
+		}
+	}
+}
+
+func TestCertLegendEntries(t *testing.T) {
+	entries := certLegendEntries(map[string]string{"pool_retirement": "🪦"})
+	if len(entries) != len(certKinds) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(certKinds))
+	}
+	last := entries[len(entries)-1]
+	if last.Icon != "🪦" || last.Name != "Pool Retire" {
+		t.Errorf("override not applied: %+v", last)
+	}
+}
+
+func TestRenderLegend(t *testing.T) {
+	tests := []struct {
+		name     string
+		width    int
+		wantRows int
+		fits     bool
+	}{
+		{"narrow", 40, 6, true},
+		{"default", 80, 3, true},
+		{"unknown", 0, 3, true},
+		{"wide", 200, 1, true},
+		{"narrower than an entry", 10, 11, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			text, rows := renderLegend(defaultLegendEntries, test.width)
+			if rows != test.wantRows || strings.Count(text, "\n") != rows-1 {
+				t.Errorf("got %d rows, want %d: %q", rows, test.wantRows, text)
+			}
+			width := test.width
+			if width == 0 {
+				width = defaultLegendWidth
+			}
+			for _, line := range strings.Split(text, "\n") {
+				got := tview.TaggedStringWidth(line)
+				if test.fits && got > width {
+					t.Errorf("line is %d cells, over %d: %q", got, width, line)
+				}
+			}
+			// Every entry is shown whole, never cut off mid icon
+			for _, entry := range defaultLegendEntries {
+				if !strings.Contains(text, entry.Icon+" ") {
+					t.Errorf("%s missing from %q", entry.Name, text)
+				}
+			}
+		})
+	}
+	if text, rows := renderLegend(nil, 80); text != "" || rows != 0 {
+		t.Errorf("got (%q, %d) for no entries", text, rows)
+	}
+}
+
+func TestValidateLegendCategories(t *testing.T) {
+	categories := []string{" DeFi ", "nft"}
+	if err := validateLegendCategories(categories); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if categories[0] != "defi" {
+		t.Errorf("not normalized: %q", categories[0])
+	}
+	if err := validateLegendCategories([]string{"memes"}); err == nil {
+		t.Error("expected an error for an unknown category")
+	}
+}
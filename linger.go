@@ -0,0 +1,62 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"sync"
+)
+
+var txLinger = &Linger{}
+
+// Keeps showing the previous transactions for one refresh when the mempool
+// suddenly empties, so the list doesn't vanish in a blink
+type Linger struct {
+	sync.Mutex
+	enabled   bool
+	previous  []TxRecord
+	lingering bool
+}
+
+// Returns the transactions to keep showing for this refresh, which is only
+// the previous ones on the first refresh after the mempool emptied
+func (l *Linger) Update(records []TxRecord) []TxRecord {
+	if !l.enabled {
+		return nil
+	}
+	l.Lock()
+	defer l.Unlock()
+	if len(records) > 0 {
+		l.previous = records
+		l.lingering = false
+		return nil
+	}
+	if l.lingering || len(l.previous) == 0 {
+		// Lingered for a refresh already, so let the list clear
+		l.previous = nil
+		l.lingering = false
+		return nil
+	}
+	l.lingering = true
+	return l.previous
+}
+
+// Greys out a formatted transaction list
+func greyOut(text string) string {
+	return strings.NewReplacer(
+		"[white]", "[gray]",
+		"[blue]", "[gray]",
+	).Replace(text)
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "testing"
+
+func TestLingerUpdate(t *testing.T) {
+	records := []TxRecord{{Hash: "a"}}
+	l := &Linger{enabled: true}
+	steps := []struct {
+		records []TxRecord
+		want    int
+	}{
+		{records, 0},
+		// Emptied, so the previous transactions linger once
+		{nil, 1},
+		{nil, 0},
+		{nil, 0},
+		{records, 0},
+		{nil, 1},
+	}
+	for i, step := range steps {
+		if got := len(l.Update(step.records)); got != step.want {
+			t.Errorf("step %d: got %d lingering, want %d", i, got, step.want)
+		}
+	}
+	disabled := &Linger{}
+	disabled.Update(records)
+	if got := disabled.Update(nil); got != nil {
+		t.Errorf("disabled linger returned %+v", got)
+	}
+}
+
+func TestGreyOut(t *testing.T) {
+	got := greyOut("[white]a [blue]b[white]")
+	if got != "[gray]a [gray]b[gray]" {
+		t.Errorf("got %q", got)
+	}
+}
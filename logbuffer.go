@@ -0,0 +1,96 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"sync"
+)
+
+// Maximum number of log lines kept in memory
+const logBufferLines = 1000
+
+var logBuffer = NewLogBuffer(logBufferLines)
+
+// Keeps the most recent lines written to it, for logging while the UI owns
+// the terminal
+type LogBuffer struct {
+	sync.Mutex
+	lines    []string
+	maxLines int
+	partial  string
+}
+
+func NewLogBuffer(maxLines int) *LogBuffer {
+	return &LogBuffer{
+		maxLines: maxLines,
+	}
+}
+
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	data := b.partial + string(p)
+	lines := strings.Split(data, "\n")
+	// The last element is whatever follows the final newline
+	b.partial = lines[len(lines)-1]
+	b.lines = append(b.lines, lines[:len(lines)-1]...)
+	if len(b.lines) > b.maxLines {
+		b.lines = b.lines[len(b.lines)-b.maxLines:]
+	}
+	return len(p), nil
+}
+
+// Returns up to the last n complete lines
+func (b *LogBuffer) Tail(n int) []string {
+	b.Lock()
+	defer b.Unlock()
+	if n > len(b.lines) {
+		n = len(b.lines)
+	}
+	tail := make([]string, n)
+	copy(tail, b.lines[len(b.lines)-n:])
+	return tail
+}
+
+func (b *LogBuffer) String() string {
+	b.Lock()
+	defer b.Unlock()
+	if len(b.lines) == 0 {
+		return ""
+	}
+	return strings.Join(b.lines, "\n") + "\n"
+}
+
+// Returns the last n complete lines for printing, or every line when n is
+// zero or less
+func (b *LogBuffer) Dump(n int) string {
+	if n <= 0 {
+		return b.String()
+	}
+	tail := b.Tail(n)
+	if len(tail) == 0 {
+		return ""
+	}
+	return strings.Join(tail, "\n") + "\n"
+}
+
+// Returns the log lines printed on exit, which are left out in quiet mode
+func exitLogDump(cfg *Config, buffer *LogBuffer) string {
+	if cfg.App.Quiet {
+		return ""
+	}
+	return buffer.Dump(int(cfg.App.LogDumpLines))
+}
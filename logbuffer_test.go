@@ -0,0 +1,57 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "testing"
+
+func TestLogBuffer(t *testing.T) {
+	b := NewLogBuffer(3)
+	_, _ = b.Write([]byte("one\ntwo\nthr"))
+	_, _ = b.Write([]byte("ee\nfour\nfive"))
+	// The partial last line isn't kept until it's finished
+	if got := b.String(); got != "two\nthree\nfour\n" {
+		t.Errorf("got %q", got)
+	}
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "two\nthree\nfour\n"},
+		{-1, "two\nthree\nfour\n"},
+		{2, "three\nfour\n"},
+		{10, "two\nthree\nfour\n"},
+	}
+	for _, test := range tests {
+		if got := b.Dump(test.n); got != test.want {
+			t.Errorf("dump %d: got %q, want %q", test.n, got, test.want)
+		}
+	}
+	if got := NewLogBuffer(3).Dump(2); got != "" {
+		t.Errorf("got %q from an empty buffer", got)
+	}
+}
+
+func TestExitLogDump(t *testing.T) {
+	buffer := NewLogBuffer(10)
+	_, _ = buffer.Write([]byte("one\ntwo\n"))
+	cfg := testConfig()
+	if got := exitLogDump(cfg, buffer); got != "one\ntwo\n" {
+		t.Errorf("got %q, want both lines", got)
+	}
+	cfg.App.Quiet = true
+	if got := exitLogDump(cfg, buffer); got != "" {
+		t.Errorf("expected no dump in quiet mode, got %q", got)
+	}
+}
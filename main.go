@@ -15,16 +15,22 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
+	"log"
 	"os"
+	"os/signal"
+	"reflect"
+	"runtime/debug"
+	"slices"
 	"strings"
+	"sync/atomic"
+	"syscall"
 	"time"
 
-	models "github.com/blinklabs-io/cardano-models"
 	ouroboros "github.com/blinklabs-io/gouroboros"
 	"github.com/blinklabs-io/gouroboros/ledger"
-	lcommon "github.com/blinklabs-io/gouroboros/ledger/common"
-	"github.com/fxamacker/cbor/v2"
 	"github.com/gdamore/tcell/v2"
 	"github.com/kelseyhightower/envconfig"
 	"github.com/rivo/tview"
@@ -32,9 +38,31 @@ import (
 
 var globalConfig = &Config{
 	App: AppConfig{
-		Network: "",
-		Refresh: 3,
-		Retries: 3,
+		Network:       "",
+		Refresh:       RefreshInterval(3 * time.Second),
+		Retries:       3,
+		RetryBackoff:  60,
+		SortBy:        "size",
+		TimeOrder:     "newest",
+		ColumnSep:     " ",
+		UnknownEnv:    "ignore",
+		RecoverPanics: true,
+		FeeRateUnit:   "lovelace/byte",
+		FeeUnit:       "ada",
+		ArchiveKeep:   48,
+		IndexMode:     "global",
+		PageSize:      20,
+		CapacityUnit:  "bytes",
+		TimeFormat:    "relative",
+		Transport:     "auto",
+		StatsWindow:   300,
+		IdleTimeout:   300,
+		AddressFormat: "bech32",
+		KeepSelection: true,
+		FakeSeed:      1,
+		FakeCount:     40,
+		FakeMinSize:   250,
+		FakeMaxSize:   16384,
 	},
 	Node: NodeConfig{
 		Network:    "mainnet",
@@ -51,17 +79,32 @@ var headerText = tview.NewTextView().
 	SetDynamicColors(true).
 	SetTextColor(tcell.ColorGreen)
 var footerText = tview.NewTextView().
-	SetDynamicColors(true).
-	SetChangedFunc(func() { app.Draw() })
+	SetDynamicColors(true)
 var legendText = tview.NewTextView().
 	SetDynamicColors(true).
 	SetTextColor(tcell.ColorGreen)
 var text = tview.NewTextView().
-	SetDynamicColors(true).
-	SetChangedFunc(func() { app.Draw() })
+	SetDynamicColors(true)
+var watchText = tview.NewTextView().
+	SetDynamicColors(true)
+
+// Shared between the UI event loop and the refresh loop
+var paused atomic.Bool
+
+// Queues an update to run on the tview event loop followed by a draw
+func queueUpdateDraw(update func()) {
+	app.QueueUpdateDraw(update)
+}
+
+// What the views currently show, only accessed from the tview event loop
+var displayed Content
+
+// Coalesces view updates from goroutines, set up with MAX_FPS in main
+var redraw = NewRedrawThrottle(0, queueUpdateDraw)
 
-var paused bool = false
-var content string
+// Used to spot stalls in the refresh loop
+var startTime = time.Now()
+var refreshCount atomic.Uint64
 
 // These are populated at build time
 var Version string
@@ -81,9 +124,149 @@ type Config struct {
 }
 
 type AppConfig struct {
-	Network string `envconfig:"NETWORK"`
-	Refresh uint32 `envconfig:"REFRESH"`
-	Retries uint32 `envconfig:"RETRIES"`
+	Network      string          `envconfig:"NETWORK"`
+	Title        string          `envconfig:"TITLE"`
+	Transport    string          `envconfig:"TRANSPORT"`
+	Refresh      RefreshInterval `envconfig:"REFRESH"`
+	SizesRefresh RefreshInterval `envconfig:"SIZES_REFRESH"`
+	// Longest interval refreshes slow to while the mempool stays empty
+	IdleMaxRefresh RefreshInterval `envconfig:"IDLE_MAX_REFRESH"`
+	Retries        uint32          `envconfig:"RETRIES"`
+	RetryBackoff   uint32          `envconfig:"RETRY_BACKOFF"`
+	SortBy         string          `envconfig:"SORT_BY"`
+	TimeOrder      string          `envconfig:"TIME_ORDER"`
+	TimeFormat     string          `envconfig:"TIME_FORMAT"`
+	ColumnSep      string          `envconfig:"COLUMN_SEP"`
+	UnknownEnv     string          `envconfig:"UNKNOWN_ENV"`
+	Demo           bool            `envconfig:"DEMO"`
+	// Generate a synthetic mempool instead of connecting to a node, shaped
+	// by the FAKE_ settings
+	FakeData     bool   `envconfig:"FAKE_DATA"`
+	FakeSeed     int64  `envconfig:"FAKE_SEED"`
+	FakeCount    uint32 `envconfig:"FAKE_COUNT"`
+	FakeMinSize  uint32 `envconfig:"FAKE_MIN_SIZE"`
+	FakeMaxSize  uint32 `envconfig:"FAKE_MAX_SIZE"`
+	RedactHashes bool   `envconfig:"REDACT_HASHES"`
+	// Overrides for certificate icons, such as stake_delegation:🤝
+	CertIcons map[string]string `envconfig:"CERT_ICONS"`
+	// Legend categories to show, or all when empty
+	LegendCategories []string `envconfig:"LEGEND_CATEGORIES"`
+	// Metadata larger than this isn't parsed for classifying, or zero for
+	// no limit
+	MaxMetadataBytes uint32 `envconfig:"MAX_METADATA_BYTES"`
+	// Only show transactions which didn't match a known protocol
+	UnlabeledOnly bool `envconfig:"UNLABELED_ONLY"`
+	// Address to serve the HTTP API on, such as :8080, or empty to disable
+	APIAddress string `envconfig:"API_ADDRESS"`
+	// Seconds of per-label statistics served at /stats
+	StatsWindow uint32 `envconfig:"STATS_WINDOW"`
+	// Named pipe each refresh's snapshot is written to as JSON
+	FIFOPath string `envconfig:"FIFO_PATH"`
+	// StatsD server metrics are sent to each refresh, such as
+	// localhost:8125, or empty to disable
+	StatsdAddress string `envconfig:"STATSD_ADDR"`
+	// Size in bytes over which a transaction is alerted for, or zero to
+	// not alert
+	LargeTxAlert uint32 `envconfig:"LARGE_TX_ALERT"`
+	// URL large transaction alerts are posted to as JSON
+	LargeTxWebhook string `envconfig:"LARGE_TX_WEBHOOK"`
+	// Minutes between snapshots written to ARCHIVE_DIR, or zero to not
+	// archive
+	ArchiveInterval uint32 `envconfig:"ARCHIVE_INTERVAL"`
+	// Directory snapshots are archived to
+	ArchiveDir string `envconfig:"ARCHIVE_DIR"`
+	// Most recent archives kept, or zero to keep all of them
+	ArchiveKeep uint32 `envconfig:"ARCHIVE_KEEP"`
+	// Most transactions to classify, sampling when there are more, or zero
+	// to classify all of them
+	SampleSize uint32 `envconfig:"SAMPLE_SIZE"`
+	// Most recent log lines printed on exit, or zero for all of them
+	LogDumpLines uint32 `envconfig:"LOG_DUMP_LINES"`
+	// Don't print the log lines on exit
+	Quiet bool `envconfig:"QUIET"`
+	// Print totals for the run, such as the peak mempool size, on exit
+	ExitSummary bool `envconfig:"EXIT_SUMMARY"`
+	// Enables mouse support, such as scrolling the transactions
+	Mouse bool `envconfig:"MOUSE"`
+	// Enables debugging aids, such as the raw GetSizes page
+	Debug bool `envconfig:"DEBUG"`
+	// Maximum number of redraws per second, or zero for no limit
+	MaxFPS uint32 `envconfig:"MAX_FPS"`
+	// Seconds without keypresses or mempool changes before dimming the
+	// screen, or zero to never dim
+	DimAfter uint32 `envconfig:"DIM_AFTER"`
+	// Seconds paused before disconnecting from the node, or zero to stay
+	// connected
+	IdleTimeout uint32 `envconfig:"IDLE_TIMEOUT"`
+	// File the seen transactions are saved to on exit and loaded from at
+	// startup, so ages carry over between runs
+	SeenFile string `envconfig:"SEEN_FILE"`
+	// Detect the network from the node when no network or magic is set
+	AutoNetwork bool `envconfig:"AUTO_NETWORK"`
+	// Seconds to list transactions after they leave the mempool, or zero to
+	// not list them
+	RecentlyCleared uint32 `envconfig:"RECENTLY_CLEARED"`
+	// Command run every refresh interval with sh, or cmd on Windows, with
+	// its exit status shown in the header
+	HealthCmd string `envconfig:"HEALTH_CMD"`
+	// Keep showing the previous transactions for a refresh when the mempool
+	// empties
+	Linger bool `envconfig:"LINGER"`
+	// Keep refreshing after a panic during a refresh, rather than crashing
+	RecoverPanics bool `envconfig:"RECOVER_PANICS"`
+	// Transaction hash prefixes shown in the watch pane
+	WatchHashes []string `envconfig:"WATCH_HASHES"`
+	// Inputs, as txhash#index, whose spending transactions are highlighted
+	WatchUTxOs []string `envconfig:"WATCH_UTXO"`
+	// How addresses are written by --output: bech32, hex or both
+	AddressFormat string `envconfig:"ADDRESS_FORMAT"`
+	// JSON file of address and message labels, added to the built in ones
+	LabelsFile string `envconfig:"LABELS_FILE"`
+	// JSON file of exchange and bridge wallets to label transactions with
+	WalletsFile string `envconfig:"WALLETS_FILE"`
+	// Show the watch pane at startup
+	ShowWatchPane bool `envconfig:"SHOW_WATCH_PANE"`
+	// Show the mix of icons in the mempool above the transactions
+	ShowRibbon bool `envconfig:"SHOW_RIBBON"`
+	// Show the largest transaction above the transactions, regardless of
+	// the sort and filters
+	ShowLargest bool `envconfig:"SHOW_LARGEST"`
+	// Show the largest mempool size and transaction count since launch
+	ShowPeak bool `envconfig:"SHOW_PEAK"`
+	// Start the UI before the first fetch completes
+	SkipInitialFetch bool `envconfig:"SKIP_INITIAL_FETCH"`
+	// Keep the transaction picked with the go to prompt selected across
+	// refreshes
+	KeepSelection bool `envconfig:"KEEP_SELECTION"`
+	// Optional columns
+	ShowIndex     bool `envconfig:"SHOW_INDEX"`
+	ShowSigners   bool `envconfig:"SHOW_SIGNERS"`
+	ShowRefInputs bool `envconfig:"SHOW_REF_INPUTS"`
+	ShowRedeemers bool `envconfig:"SHOW_REDEEMERS"`
+	ShowEra       bool `envconfig:"SHOW_ERA"`
+	ShowMetadata  bool `envconfig:"SHOW_METADATA"`
+	ShowFee       bool `envconfig:"SHOW_FEE"`
+	ShowFeeRate   bool `envconfig:"SHOW_FEE_RATE"`
+	ShowExpiry    bool `envconfig:"SHOW_EXPIRY"`
+	ShowValue     bool `envconfig:"SHOW_VALUE"`
+	// Whether SHOW_INDEX numbers the whole list or restarts every page
+	IndexMode string `envconfig:"INDEX_MODE"`
+	PageSize  uint32 `envconfig:"PAGE_SIZE"`
+	// Most transactions listed after sorting, or zero for all of them
+	MaxDisplayedTransactions uint32 `envconfig:"MAX_DISPLAYED_TRANSACTIONS"`
+	// Show a grid of short hashes instead of the table
+	Compact bool `envconfig:"COMPACT"`
+	// Show transactions without a fee in their own section
+	GroupByFee bool `envconfig:"GROUP_BY_FEE"`
+	// Either lovelace/byte or ada/kb
+	FeeRateUnit string `envconfig:"FEERATE_UNIT"`
+	// Either ada or lovelace
+	FeeUnit string `envconfig:"FEE_UNIT"`
+	// Either bytes, kb or mb
+	CapacityUnit string `envconfig:"CAPACITY_UNIT"`
+	// Palette for status cues, see themes. Picked from the terminal's
+	// capabilities when empty
+	Theme string `envconfig:"THEME"`
 }
 
 type NodeConfig struct {
@@ -99,6 +282,13 @@ func LoadConfig() (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error processing environment: %s", err)
 	}
+	err = globalConfig.checkUnknownEnv(os.Environ(), os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+	if err := globalConfig.Validate(); err != nil {
+		return nil, err
+	}
 	if err := globalConfig.populateNetworkMagic(); err != nil {
 		return nil, err
 	}
@@ -109,7 +299,225 @@ func GetConfig() *Config {
 	return globalConfig
 }
 
-// Populates NetworkMagic from named networks
+// Shortest REFRESH
+const minRefresh = RefreshInterval(minRefreshInterval)
+
+// Normalizes and checks values which only accept a fixed set of options
+func (c *Config) Validate() error {
+	c.App.SortBy = strings.ToLower(strings.TrimSpace(c.App.SortBy))
+	if !isValidSortBy(c.App.SortBy) {
+		return fmt.Errorf(
+			"invalid SORT_BY: %q (expected one of: %s)",
+			c.App.SortBy,
+			strings.Join(sortByValues, ", "),
+		)
+	}
+	c.App.AddressFormat = strings.ToLower(
+		strings.TrimSpace(c.App.AddressFormat),
+	)
+	if !slices.Contains(addressFormats, c.App.AddressFormat) {
+		return fmt.Errorf(
+			"invalid ADDRESS_FORMAT: %q (expected one of: %s)",
+			c.App.AddressFormat,
+			strings.Join(addressFormats, ", "),
+		)
+	}
+	c.App.TimeOrder = strings.ToLower(strings.TrimSpace(c.App.TimeOrder))
+	if !slices.Contains(timeOrderValues, c.App.TimeOrder) {
+		return fmt.Errorf(
+			"invalid TIME_ORDER: %q (expected one of: %s)",
+			c.App.TimeOrder,
+			strings.Join(timeOrderValues, ", "),
+		)
+	}
+	c.App.IndexMode = strings.ToLower(strings.TrimSpace(c.App.IndexMode))
+	if !slices.Contains(indexModes, c.App.IndexMode) {
+		return fmt.Errorf(
+			"invalid INDEX_MODE: %q (expected one of: %s)",
+			c.App.IndexMode,
+			strings.Join(indexModes, ", "),
+		)
+	}
+	if c.App.IndexMode == "page" && c.App.PageSize == 0 {
+		return fmt.Errorf("PAGE_SIZE must be at least 1 with INDEX_MODE page")
+	}
+	c.App.Transport = strings.ToLower(strings.TrimSpace(c.App.Transport))
+	if !slices.Contains(transports, c.App.Transport) {
+		return fmt.Errorf(
+			"invalid TRANSPORT: %q (expected one of: %s)",
+			c.App.Transport,
+			strings.Join(transports, ", "),
+		)
+	}
+	c.App.TimeFormat = strings.ToLower(strings.TrimSpace(c.App.TimeFormat))
+	if !slices.Contains(timeFormats, c.App.TimeFormat) {
+		return fmt.Errorf(
+			"invalid TIME_FORMAT: %q (expected one of: %s)",
+			c.App.TimeFormat,
+			strings.Join(timeFormats, ", "),
+		)
+	}
+	c.App.FeeRateUnit = strings.ToLower(strings.TrimSpace(c.App.FeeRateUnit))
+	if !slices.Contains(feeRateUnits, c.App.FeeRateUnit) {
+		return fmt.Errorf(
+			"invalid FEERATE_UNIT: %q (expected one of: %s)",
+			c.App.FeeRateUnit,
+			strings.Join(feeRateUnits, ", "),
+		)
+	}
+	c.App.FeeUnit = strings.ToLower(strings.TrimSpace(c.App.FeeUnit))
+	if !slices.Contains(feeUnits, c.App.FeeUnit) {
+		return fmt.Errorf(
+			"invalid FEE_UNIT: %q (expected one of: %s)",
+			c.App.FeeUnit,
+			strings.Join(feeUnits, ", "),
+		)
+	}
+	c.App.CapacityUnit = strings.ToLower(strings.TrimSpace(c.App.CapacityUnit))
+	if !slices.Contains(capacityUnits, c.App.CapacityUnit) {
+		return fmt.Errorf(
+			"invalid CAPACITY_UNIT: %q (expected one of: %s)",
+			c.App.CapacityUnit,
+			strings.Join(capacityUnits, ", "),
+		)
+	}
+	c.App.Theme = strings.ToLower(strings.TrimSpace(c.App.Theme))
+	if c.App.Theme != "" {
+		if err := validateTheme(c.App.Theme); err != nil {
+			return err
+		}
+	}
+	if err := validateCertIcons(c.App.CertIcons); err != nil {
+		return err
+	}
+	if err := validateLegendCategories(c.App.LegendCategories); err != nil {
+		return err
+	}
+	if err := validateWatchUTxOs(c.App.WatchUTxOs); err != nil {
+		return err
+	}
+	for i, hash := range c.App.WatchHashes {
+		c.App.WatchHashes[i] = strings.ToLower(strings.TrimSpace(hash))
+	}
+	if c.App.ColumnSep == "" {
+		c.App.ColumnSep = " "
+	}
+	if c.App.FakeMinSize > c.App.FakeMaxSize {
+		return fmt.Errorf(
+			"FAKE_MIN_SIZE (%d) is larger than FAKE_MAX_SIZE (%d)",
+			c.App.FakeMinSize,
+			c.App.FakeMaxSize,
+		)
+	}
+	if c.App.ArchiveInterval > 0 && c.App.ArchiveDir == "" {
+		return fmt.Errorf("ARCHIVE_INTERVAL is set without an ARCHIVE_DIR")
+	}
+	// Refreshing back to back would hammer the node
+	if c.App.Refresh < minRefresh {
+		c.App.Refresh = minRefresh
+	}
+	if c.App.SizesRefresh > 0 && c.App.SizesRefresh < minRefresh {
+		return fmt.Errorf(
+			"SIZES_REFRESH (%s) is below the minimum of %s",
+			c.App.SizesRefresh,
+			minRefresh,
+		)
+	}
+	if c.App.IdleMaxRefresh > 0 && c.App.IdleMaxRefresh < c.App.Refresh {
+		return fmt.Errorf(
+			"IDLE_MAX_REFRESH (%s) is below REFRESH (%s)",
+			c.App.IdleMaxRefresh,
+			c.App.Refresh,
+		)
+	}
+	return nil
+}
+
+// Warns about, on w, or rejects TXTOP_ variables which don't match a known
+// field
+func (c *Config) checkUnknownEnv(environ []string, w io.Writer) error {
+	switch strings.ToLower(c.App.UnknownEnv) {
+	case "", "ignore":
+		return nil
+	case "warn":
+		for _, name := range unknownEnvVars("txtop", c, environ) {
+			fmt.Fprintf(
+				w,
+				"warning: unknown environment variable: %s\n",
+				name,
+			)
+		}
+		return nil
+	case "fail":
+		unknown := unknownEnvVars("txtop", c, environ)
+		if len(unknown) > 0 {
+			return fmt.Errorf(
+				"unknown environment variables: %s",
+				strings.Join(unknown, ", "),
+			)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown UNKNOWN_ENV mode: %s", c.App.UnknownEnv)
+	}
+}
+
+// Returns the names of prefixed variables in environ which envconfig would
+// not use to populate spec
+func unknownEnvVars(
+	prefix string,
+	spec interface{},
+	environ []string,
+) []string {
+	known := make(map[string]bool)
+	envKeys(strings.ToUpper(prefix), reflect.TypeOf(spec), known)
+	var unknown []string
+	for _, entry := range environ {
+		name, _, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(name, strings.ToUpper(prefix)+"_") {
+			continue
+		}
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	return unknown
+}
+
+// Collects the prefixed keys envconfig derives from the struct tags of t,
+// following the same naming rules for nested structs
+func envKeys(prefix string, t reflect.Type, keys map[string]bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("ignored") == "true" {
+			continue
+		}
+		key := field.Name
+		if tag := field.Tag.Get("envconfig"); tag != "" {
+			key = tag
+		}
+		key = strings.ToUpper(prefix + "_" + key)
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct {
+			innerPrefix := key
+			if field.Anonymous {
+				innerPrefix = prefix
+			}
+			envKeys(innerPrefix, fieldType, keys)
+			continue
+		}
+		keys[key] = true
+	}
+}
+
+// Populates NetworkMagic from named networks. AUTO_NETWORK takes precedence
+// over CARDANO_NETWORK, which always has a default, but not over NETWORK
 func (c *Config) populateNetworkMagic() error {
 	if c.Node.NetworkMagic == 0 {
 		if c.App.Network != "" {
@@ -122,6 +530,17 @@ func (c *Config) populateNetworkMagic() error {
 			c.Node.NetworkMagic = uint32(network.NetworkMagic)
 			c.Node.SocketPath = "/ipc/node.socket"
 			return nil
+		} else if c.App.AutoNetwork {
+			name, magic, err := detectNetwork(
+				autoNetworkCandidates,
+				networkHandshake,
+			)
+			if err != nil {
+				return fmt.Errorf("unable to detect network: %w", err)
+			}
+			c.Node.Network = name
+			c.Node.NetworkMagic = magic
+			return nil
 		} else if c.Node.Network != "" {
 			network, ok := ouroboros.NetworkByName(c.Node.Network)
 			if !ok {
@@ -137,290 +556,1093 @@ func (c *Config) populateNetworkMagic() error {
 }
 
 func GetConnection(errorChan chan error) (*ouroboros.Connection, error) {
+	return connectWithMagic(GetConfig().Node.NetworkMagic, errorChan)
+}
+
+// Valid values for Transport
+var transports = []string{"auto", "tcp", "unix"}
+
+// Decides whether to connect over TCP or a UNIX socket. With auto, TCP is
+// used when an address and port are set, otherwise the socket path
+func selectTransport(transport string, node NodeConfig) (string, error) {
+	hasTCP := node.Address != "" && node.Port > 0
+	hasSocket := node.SocketPath != ""
+	switch transport {
+	case "tcp":
+		if !hasTCP {
+			return "", newConnectionError(
+				ErrNoEndpoint,
+				nil,
+				"TRANSPORT is tcp but the node address/port are not set",
+			)
+		}
+		return "tcp", nil
+	case "unix":
+		if !hasSocket {
+			return "", newConnectionError(
+				ErrNoEndpoint,
+				nil,
+				"TRANSPORT is unix but the node socket path is not set",
+			)
+		}
+		return "unix", nil
+	case "auto":
+	default:
+		return "", fmt.Errorf(
+			"invalid TRANSPORT: %q (expected one of: %s)",
+			transport,
+			strings.Join(transports, ", "),
+		)
+	}
+	if hasTCP {
+		return "tcp", nil
+	}
+	if hasSocket {
+		return "unix", nil
+	}
+	return "", newConnectionError(
+		ErrNoEndpoint,
+		nil,
+		"specify either the UNIX socket path or the address/port",
+	)
+}
+
+// Connects to the configured node, handshaking with the given network magic
+func connectWithMagic(
+	networkMagic uint32,
+	errorChan chan error,
+) (*ouroboros.Connection, error) {
 	cfg := GetConfig()
+	transport, err := selectTransport(cfg.App.Transport, cfg.Node)
+	if err != nil {
+		return nil, err
+	}
 	oConn, err := ouroboros.NewConnection(
-		ouroboros.WithNetworkMagic(uint32(cfg.Node.NetworkMagic)),
+		ouroboros.WithNetworkMagic(networkMagic),
 		ouroboros.WithErrorChan(errorChan),
 		ouroboros.WithNodeToNode(false),
 		ouroboros.WithKeepAlive(true),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failure creating ouroboros connection: %s", err)
+		return nil, fmt.Errorf("failure creating ouroboros connection: %w", err)
 	}
-	if cfg.Node.Address != "" && cfg.Node.Port > 0 {
+	if transport == "tcp" {
 		err := oConn.Dial(
 			"tcp",
 			fmt.Sprintf("%s:%d", cfg.Node.Address, cfg.Node.Port),
 		)
 		if err != nil {
-			return nil, fmt.Errorf(
-				"failure connecting to node via TCP: %s",
+			return nil, newConnectionError(
+				dialErrorKind(err),
 				err,
+				fmt.Sprintf("failure connecting to node via TCP: %s", err),
 			)
 		}
-	} else if cfg.Node.SocketPath != "" {
+	} else {
 		_, err := os.Stat(cfg.Node.SocketPath)
 		if err != nil {
 			if os.IsNotExist(err) {
-				return nil, fmt.Errorf(
-					"node socket path does not exist: %s",
-					cfg.Node.SocketPath,
+				return nil, newConnectionError(
+					ErrDialFailed,
+					err,
+					fmt.Sprintf(
+						"node socket path does not exist: %s",
+						cfg.Node.SocketPath,
+					),
 				)
 			} else {
-				return nil, fmt.Errorf(
-					"unknown error checking if node socket path exists: %s",
+				return nil, newConnectionError(
+					ErrDialFailed,
 					err,
+					fmt.Sprintf(
+						"unknown error checking if node socket path exists: %s",
+						err,
+					),
 				)
 			}
 		}
 		err = oConn.Dial("unix", cfg.Node.SocketPath)
 		if err != nil {
-			return nil, fmt.Errorf(
-				"failure connecting to node via UNIX socket: %s",
+			return nil, newConnectionError(
+				dialErrorKind(err),
 				err,
+				fmt.Sprintf(
+					"failure connecting to node via UNIX socket: %s",
+					err,
+				),
 			)
 		}
-	} else {
-		return nil, fmt.Errorf(
-			"specify either the UNIX socket path or the address/port",
-		)
 	}
 	return oConn, nil
 }
 
-func GetSizes(oConn *ouroboros.Connection) string {
-	if oConn == nil {
-		return " [red]failed to connect to node"
-	}
-	capacity, size, numberOfTxs, err := oConn.LocalTxMonitor().Client.GetSizes()
+// Sizes reported by the node for its mempool
+type MempoolSizes struct {
+	Capacity    uint32
+	Size        uint32
+	NumberOfTxs uint32
+}
+
+// Reads the sizes of the acquired mempool snapshot, acquiring one if needed
+func GetSizes(client mempoolClient) (MempoolSizes, error) {
+	capacity, size, numberOfTxs, err := client.GetSizes()
 	if err != nil {
-		return fmt.Sprintf(" [red]ERROR: GetSizes: %s", err)
+		return MempoolSizes{}, fmt.Errorf("GetSizes: %s", err)
 	}
-	return fmt.Sprintf(
-		" [white]Mempool size (bytes): [blue]%-10d[white] Mempool capacity (bytes): [blue]%-10d[white] Transactions: [blue]%-10d[white]\n",
-		size,
-		capacity,
-		numberOfTxs,
-	)
+	sizes := MempoolSizes{
+		Capacity:    capacity,
+		Size:        size,
+		NumberOfTxs: numberOfTxs,
+	}
+	sizesHistory.Add(time.Now(), sizes)
+	return sizes, nil
 }
 
-func GetTransactions(oConn *ouroboros.Connection) string {
-	if oConn == nil {
-		return ""
+// Formats the sizes line. The drained count is shown as the number of
+// transactions, noting the count the node reported if it differs
+func FormatSizes(
+	sizes MempoolSizes,
+	drained int,
+	capacityUnit string,
+) string {
+	var reported string
+	if countMismatch(sizes.NumberOfTxs, drained) {
+		reported = fmt.Sprintf(
+			" %s(node reported %d)[white]",
+			warningTag(),
+			sizes.NumberOfTxs,
+		)
 	}
-	var sb strings.Builder
-	// sb.WriteString(" [white]Transactions:\n")
-	sb.WriteString(
-		fmt.Sprintf(" [white]%-10s %-10s %s\n", "Size:", "Icon:", "TxHash:"),
+	capacityLabel := "Mempool capacity (bytes)"
+	if capacityUnit != "bytes" {
+		capacityLabel = "Mempool capacity"
+	}
+	return fmt.Sprintf(
+		" [white]Mempool size (bytes): [blue]%-10d[white] %s: [blue]%-10s[white] Transactions: [blue]%-10d[white]%s\n",
+		sizes.Size,
+		capacityLabel,
+		formatCapacity(sizes.Capacity, capacityUnit),
+		drained,
+		reported,
 	)
+}
+
+// Reports whether the count from GetSizes differs from the number of
+// transactions drained, which happens when the mempool changes between calls
+func countMismatch(reported uint32, drained int) bool {
+	return int64(reported) != int64(drained)
+}
+
+// Drains the mempool, returning the transactions read before any error
+func GetTransactions(client mempoolClient) ([][]byte, error) {
+	var txs [][]byte
 	for {
-		txRawBytes, err := oConn.LocalTxMonitor().Client.NextTx()
+		txRawBytes, err := client.NextTx()
 		if err != nil {
-			sb.WriteString(fmt.Sprintf(" [red]ERROR: NextTx: %s\n", err))
-			return fmt.Sprint(sb.String())
+			return txs, fmt.Errorf("NextTx: %s", err)
 		}
 		if txRawBytes == nil {
 			break
 		}
-		size := len(txRawBytes)
-		txType, err := ledger.DetermineTransactionType(txRawBytes)
-		if err != nil {
-			sb.WriteString(fmt.Sprintf(" [red]ERROR: TxType: %s\n", err))
-			return fmt.Sprint(sb.String())
+		txs = append(txs, txRawBytes)
+	}
+	return txs, nil
+}
+
+// Formats the sizes and transactions from a single refresh
+func RenderSnapshot(cfg *Config, snapshot Snapshot) string {
+	main, _ := renderSnapshot(cfg, snapshot)
+	return main
+}
+
+// Renders a snapshot as RenderSnapshot does, also returning the lines
+// holding the transaction list
+func renderSnapshot(cfg *Config, snapshot Snapshot) (string, lineRange) {
+	var sb strings.Builder
+	sb.WriteString(snapshot.Warning)
+	if snapshot.SizesErr != nil {
+		sb.WriteString(
+			fmt.Sprintf(" %sERROR: %s\n", errorTag(), snapshot.SizesErr),
+		)
+	} else {
+		sb.WriteString(
+			FormatSizes(
+				snapshot.Sizes,
+				snapshot.transactionCount(),
+				cfg.App.CapacityUnit,
+			),
+		)
+	}
+	if cfg.App.ShowPeak {
+		sb.WriteString(
+			FormatPeaks(getMempoolPeaks(), time.Now(), relativeTimes.Load()),
+		)
+	}
+	sb.WriteString("\n")
+	records := snapshot.Records
+	if cfg.App.ShowLargest {
+		sb.WriteString(FormatLargest(cfg, records))
+	}
+	if snapshot.Sampled {
+		sb.WriteString(
+			fmt.Sprintf(
+				" %sSampled: showing %d of %d transactions[white]\n",
+				warningTag(),
+				len(records),
+				snapshot.Drained,
+			),
+		)
+	}
+	sb.WriteString(FormatResidence(records, snapshot.Time))
+	sb.WriteString(
+		FormatCategoryCounts(categoryCounts(allLegendEntries(cfg), records)),
+	)
+	sb.WriteString(
+		fmt.Sprintf(
+			" [white]Unique addresses: [blue]%d[white]\n",
+			uniqueAddresses(records),
+		),
+	)
+	sb.WriteString(FormatConflicts(snapshot.Conflicts))
+	if cfg.App.ShowRibbon {
+		width := DetectTerminalCaps().Width
+		if width <= 0 {
+			width = defaultRibbonWidth
 		}
-		tx, err := ledger.NewTransactionFromCbor(txType, txRawBytes)
-		if err != nil {
-			sb.WriteString(fmt.Sprintf(" [red]ERROR: Tx: %s\n", err))
-			return fmt.Sprint(sb.String())
-		}
-		var icon string
-		// Check if Tx has metadata and compare against our list
-		if tx.Metadata() != nil {
-			mdCbor := tx.Metadata().Cbor()
-			var msgMetadata models.Cip20Metadata
-			_ = cbor.Unmarshal(mdCbor, &msgMetadata)
-			if msgMetadata.Num674.Msg != nil {
-				// Only check first line
-				switch msgMetadata.Num674.Msg[0] {
-				// Dexhunter
-				case "Dexhunter Trade":
-					icon = "🏹"
-				// Minswap
-				case "Minswap: Deposit Order",
-					"Minswap: Cancel Order",
-					"Minswap: Create Pool",
-					"Minswap: Launch Bowl Redemption",
-					"Minswap: LBE Deposit ADA",
-					"Minswap: Liquidity Migration",
-					"Minswap: MasterChef",
-					"Minswap: Order Executed",
-					"Minswap: Swap Exact In Order",
-					"Minswap: Swap Exact In Limit Order",
-					"Minswap: Swap Exact Out Order",
-					"Minswap: Swap Exact Out Limit Order",
-					"Minswap: V2 Harvest reward",
-					"Minswap: V2 Stake liquidity",
-					"Minswap: Withdraw Order",
-					"Minswap: Zap Order":
-					icon = "🐱"
-				// Sundae
-				case "SSP: Swap Request":
-					icon = "🍨"
-				}
-			}
+		// Leave room for the leading space
+		ribbon := iconRibbon(iconCounts(records), width-1)
+		if ribbon != "" {
+			sb.WriteString(" " + ribbon + "\n")
 		}
-		// Check if output includes known script addresses
-		for _, output := range tx.Outputs() {
-			switch output.Address().String() {
-			// Axo
-			case "addr1w8ytzffgwpf94dy20kgw72gn9ujjhqu3md34vhggenkakeszhjpl3",
-				"addr1z8ytzffgwpf94dy20kgw72gn9ujjhqu3md34vhggenkakejv7ncp3yppt0gcr50u60y43x32fgadhnl35u9hfqyql2pqr3p0j4":
-				icon = "❌"
-			// Dripdropz
-			case "addr1v8pr9mwnqarw808gtllvmlxvk70hnszrukjeqfstr9t9g5crud8c4":
-				icon = "🚰"
-			// Indigo
-			case "addr1w80ptp0qgmcklhmeweesqgeurtlma8fsxsr9dt8au30fzss0czhl9",
-				"addr1w92w34pys9h4h02zxdfsp8lhcvdd5t9aaln9z96szsgh73scty4aj",
-				"addr1w8q673nyx6vtcules4aqess7e9yuu6geja95xhg90hzy3wqpsjzzz",
-				"addr1wxj88juwkzmpcqacd9hua2cur2yl50kgx3tjs588c2470qc2ftfae":
-				icon = "👁️ " // space because it's only 1 char wide
-			// JPG
-			case "addr1zxgx3far7qygq0k6epa0zcvcvrevmn0ypsnfsue94nsn3tvpw288a4x0xf8pxgcntelxmyclq83s0ykeehchz2wtspks905plm":
-				icon = "🦛"
-			// Liqwid
-			case "addr1wx6htk5hfmr4dw32lhxdcp7t6xpe4jhs5fxylq90mqwnldsvr87c6",
-				"addr1wyn2aflq8ff7xaxpmqk9vz53ks28hz256tkyaj739rsvrrq3u5ft3",
-				"addr1w8arvq7j9qlrmt0wpdvpp7h4jr4fmfk8l653p9t907v2nsss7w7r4":
-				icon = "💧"
-			// Minswap
-			case "addr1z84q0denmyep98ph3tmzwsmw0j7zau9ljmsqx6a4rvaau66j2c79gy9l76sdg0xwhd7r0c0kna0tycz4y5s6mlenh8pq777e2a":
-				icon = "🐱"
-			// Optim
-			case "addr1zywj8y96k38kye7qz329dhp0t782ykr0ev92mtz4yhv6gph8ucsr8rpyzewcf9jyf7gmjj052dednasdeznehw7aqc7q0z7vn2":
-				icon = "🅾️"
-			// Silk Toad
-			case "addr1w9d85mfr73mk8pr5erd46d7e7whcah2tzcyqd5rr4hv2amg9sxgl8",
-				"addr1xxj62lufz8se8rlr7r79ap7rwa845f4gnvm6qls85kuxpw9954lcjy0pjw878u8ut6ruxa60tgn23xeh5plq0fdcvzuq7kuswe":
-				icon = "🕺"
-			// Spectrum
-			case "addr1wyr4uz0tp75fu8wrg6gm83t20aphuc9vt6n8kvu09ctkugqpsrmeh",
-				"addr1x94ec3t25egvhqy2n265xfhq882jxhkknurfe9ny4rl9k6dj764lvrxdayh2ux30fl0ktuh27csgmpevdu89jlxppvrst84slu",
-				"addr1x8nz307k3sr60gu0e47cmajssy4fmld7u493a4xztjrll0aj764lvrxdayh2ux30fl0ktuh27csgmpevdu89jlxppvrswgxsta",
-				"addr1wynp362vmvr8jtc946d3a3utqgclfdl5y9d3kn849e359hsskr20n":
-				icon = "🌈"
-			// Sundae
-			case "addr1wxaptpmxcxawvr3pzlhgnpmzz3ql43n2tc8mn3av5kx0yzs09tqh8",
-				"addr1w9qzpelu9hn45pefc0xr4ac4kdxeswq7pndul2vuj59u8tqaxdznu",
-				"addr1w9jx45flh83z6wuqypyash54mszwmdj8r64fydafxtfc6jgrw4rm3",
-				"addr1x8srqftqemf0mjlukfszd97ljuxdp44r372txfcr75wrz26rnxqnmtv3hdu2t6chcfhl2zzjh36a87nmd6dwsu3jenqsslnz7e",
-				"addr1z8ax5k9mutg07p2ngscu3chsauktmstq92z9de938j8nqal9r9z8yaghysf05atjyv79t73lercjdqnejetxm307m49qdfqcxd":
-				icon = "🍨"
-			// VyFinance
-			case "addr1w8ll74xa05dkn69n3rmp93h8maphmms2408nt0nyruarzvqr9zf64",
-				"addr1z976yepnveus5uddth7qd66kn6cuzd7tccjd39dfdayc7lnend0q3h5twed567pu236a0sf6vfgruxgpr4rkxryyx0zqa550y7":
-				icon = "🔵"
-			// Wingriders
-			case "addr1wxr2a8htmzuhj39y2gq7ftkpxv98y2g67tg8zezthgq4jkg0a4ul4":
-				icon = "🦸"
-			}
+	}
+	// sb.WriteString(" [white]Transactions:\n")
+	filter := getFilterState()
+	sb.WriteString(describeFilters(filter))
+	var table lineRange
+	table.First = strings.Count(sb.String(), "\n")
+	if len(records) == 0 && len(snapshot.Lingering) > 0 {
+		sb.WriteString(" [gray]Mempool emptied, previous transactions:\n")
+		lingering := filterTransactions(snapshot.Lingering, filter.filters())
+		sb.WriteString(greyOut(formatList(cfg, lingering)))
+	} else if cfg.App.GroupByFee {
+		shown := filterTransactions(records, filter.filters())
+		paying, zeroFee := partitionByFee(shown)
+		sb.WriteString(" [white]Fee-paying:\n")
+		sb.WriteString(formatList(cfg, paying))
+		if len(zeroFee) > 0 {
+			sb.WriteString("\n [white]Zero-fee:\n")
+			sb.WriteString(formatList(cfg, zeroFee))
 		}
-		// Check if output includes known stake addresses
-		for _, output := range tx.Outputs() {
-			if output.Address().StakeAddress() != nil {
-				switch output.Address().StakeAddress().String() {
-				// Seal's Vending Machine
-				case "stake1u8ffzkegp8h48mare3g3ntf3xmjce3jqptsdtj38ee3yh3c9t4uum":
-					icon = "🦭"
-				}
-			}
+	} else {
+		shown := filterTransactions(records, filter.filters())
+		sb.WriteString(formatList(cfg, shown))
+	}
+	table.End = strings.Count(sb.String(), "\n")
+	if snapshot.TxErr != nil {
+		sb.WriteString(
+			fmt.Sprintf(" %sERROR: %s\n", errorTag(), snapshot.TxErr),
+		)
+	}
+	sb.WriteString(
+		FormatCleared(snapshot.Cleared, snapshot.Time, cfg.App.RedactHashes),
+	)
+	return sb.String(), table
+}
+
+// Formats records as a table, or as a grid of short hashes in compact mode
+func formatList(cfg *Config, records []TxRecord) string {
+	if !cfg.App.Compact {
+		return FormatTransactions(cfg, records)
+	}
+	width := DetectTerminalCaps().Width
+	if width <= 0 {
+		width = defaultRibbonWidth
+	}
+	shown := displayedTransactions(cfg, records)
+	return formatCompactGrid(
+		shown,
+		allLegendEntries(cfg),
+		width,
+		highlighter(cfg),
+	) + formatHidden(len(records), len(shown))
+}
+
+// Sorts and formats transaction records as a table
+func FormatTransactions(cfg *Config, records []TxRecord) string {
+	var sb strings.Builder
+	sep := cfg.App.ColumnSep
+	shown := displayedTransactions(cfg, records)
+	layout := fitLayout(
+		rowLayout{
+			showIndex: cfg.App.ShowIndex,
+			showIcon:  true,
+			columns:   optionalColumns(cfg),
+			highlight: highlighter(cfg),
+			conflicts: slices.ContainsFunc(
+				shown,
+				func(record TxRecord) bool { return record.Conflict },
+			),
+		},
+		sep,
+		DetectTerminalCaps().Width,
+	)
+	sb.WriteString(formatHeaderRow(sep, layout))
+	for i, record := range shown {
+		index, page := rowIndex(i, cfg.App.IndexMode, int(cfg.App.PageSize))
+		if layout.showIndex && page > 1 && index == 1 {
+			sb.WriteString(fmt.Sprintf(" [gray]Page %d[white]\n", page))
 		}
+		sb.WriteString(formatRow(sep, layout, record, index))
+	}
+	sb.WriteString(formatHidden(len(records), len(shown)))
+	return fmt.Sprint(sb.String())
+}
 
-		// Check if Tx has certificates and compare against known types
-		if tx.Certificates() != nil {
-			for _, certificate := range tx.Certificates() {
-				eject := false
-				switch certificate.(type) {
-				case *lcommon.StakeRegistrationCertificate, *lcommon.StakeDeregistrationCertificate, *lcommon.StakeDelegationCertificate:
-					icon = "🥩"
-					eject = true
-				case *lcommon.PoolRegistrationCertificate, *lcommon.PoolRetirementCertificate:
-					icon = "🏊"
-					eject = true
-				}
-				if eject {
-					break
-				}
-			}
+// Returns the distinct addresses a transaction pays to, in output order
+func outputAddresses(tx ledger.Transaction) []string {
+	var addresses []string
+	for _, output := range tx.Outputs() {
+		address := output.Address().String()
+		if !slices.Contains(addresses, address) {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
+
+// Counts the distinct output addresses across transactions
+func uniqueAddresses(txs []TxRecord) int {
+	seen := make(map[string]struct{})
+	for _, tx := range txs {
+		for _, address := range tx.Addresses {
+			seen[address] = struct{}{}
+		}
+	}
+	return len(seen)
+}
+
+// Returns records in the order and form they're shown, sorted, cut to
+// MAX_DISPLAYED_TRANSACTIONS and with hashes redacted if enabled
+func displayedTransactions(cfg *Config, records []TxRecord) []TxRecord {
+	sorted := sortTransactions(
+		records,
+		getSortBy(),
+		getTimeOrder(),
+		allLegendEntries(cfg),
+	)
+	if limit := int(cfg.App.MaxDisplayedTransactions); limit > 0 {
+		sorted = sorted[:min(limit, len(sorted))]
+	}
+	if cfg.App.RedactHashes {
+		sorted = redactRecords(sorted, redactKey)
+	}
+	return sorted
+}
+
+// Notes how many transactions were left out of a list, if any
+func formatHidden(total int, shown int) string {
+	if total <= shown {
+		return ""
+	}
+	return fmt.Sprintf(" [gray]... and %d more[white]\n", total-shown)
+}
+
+// A transaction from the mempool along with what we could determine about it
+type TxRecord struct {
+	Hash            string
+	Size            int
+	Icon            string
+	Label           string
+	FirstSeen       time.Time
+	RequiredSigners int
+	ReferenceInputs int
+	Fee             uint64
+	Era             string
+	// Whether the transaction carries any auxiliary data, recognized or not
+	HasMetadata     bool
+	HasCertificates bool
+	// Distinct output addresses
+	Addresses []string
+	// Lovelace paid to the outputs, not counting native assets
+	Value uint64
+	// Number of redeemers, roughly how many scripts the transaction runs
+	Redeemers int
+	// Inputs spent, as txhash#index
+	Inputs []string
+	// Whether another transaction in the mempool spends one of the inputs
+	Conflict bool
+	// First slot the transaction is no longer valid in, or zero for none
+	TTL uint64
+	// Whether it can still be included relative to the chain tip, see
+	// expiryBadge, or empty when the tip wasn't read
+	Expiry string
+}
+
+// Parses raw transaction CBOR and matches it against known protocols with
+// chain, as built by configuredClassifiers
+func ClassifyTransaction(
+	txRawBytes []byte,
+	chain []Classifier,
+) (TxRecord, error) {
+	size := len(txRawBytes)
+	txType, err := ledger.DetermineTransactionType(txRawBytes)
+	if err != nil {
+		return TxRecord{}, fmt.Errorf("TxType: %s", err)
+	}
+	tx, err := ledger.NewTransactionFromCbor(txType, txRawBytes)
+	if err != nil {
+		return TxRecord{}, fmt.Errorf("Tx: %s", err)
+	}
+	return newTxRecord(tx, txType, size, chain), nil
+}
+
+// Builds the record for a decoded transaction of size bytes, labelled by
+// the first classifier in chain recognizing it
+func newTxRecord(
+	tx ledger.Transaction,
+	txType uint,
+	size int,
+	chain []Classifier,
+) TxRecord {
+	label, icon := classifyTx(tx, chain)
+	return TxRecord{
+		Hash:            tx.Hash(),
+		Size:            size,
+		Icon:            icon,
+		Label:           label,
+		RequiredSigners: len(tx.RequiredSigners()),
+		ReferenceInputs: len(tx.ReferenceInputs()),
+		Fee:             tx.Fee(),
+		Era:             txEra(txType),
+		HasMetadata:     tx.Metadata() != nil,
+		HasCertificates: len(tx.Certificates()) > 0,
+		Addresses:       outputAddresses(tx),
+		Value:           outputValue(tx),
+		Redeemers:       redeemerCount(tx),
+		Inputs:          spentInputs(tx),
+		TTL:             tx.TTL(),
+	}
+}
+
+func GetContent(cfg *Config, errorChan chan error) Content {
+	now := time.Now()
+	if ok, nextAttempt := connBreaker.Allow(now); !ok {
+		return Content{
+			Main: fmt.Sprintf(
+				" %snode unreachable — backing off, next attempt at %s",
+				errorTag(),
+				nextAttempt.Format(time.TimeOnly),
+			),
 		}
+	}
+	snapshot, err := snapshotSource(cfg, errorChan)
+	if err != nil {
+		connectionErrors.Add(1)
+		connBreaker.Failure(now)
+		snapshotHealthy.Store(false)
+		return Content{Main: fmt.Sprintf(" %s%s", errorTag(), err)}
+	}
+	connBreaker.Success()
+	snapshotHealthy.Store(snapshot.Healthy())
+	setLastSnapshot(snapshot)
+	setPresentLabels(presentLabels(allLegendEntries(cfg), snapshot.Records))
+	recordLabelStats(cfg, snapshot)
+	alertLargeTxs(cfg, snapshot)
+	publishSnapshot(cfg, snapshot)
+	archiveSnapshot(cfg, snapshot)
+	snapshotStream.Publish(snapshot)
+	sendStatsd(snapshot)
+	return renderContent(cfg, snapshot)
+}
 
-		spaces := "10"
-		if icon != "" {
-			spaces = "9"
+// Renders the text of each pane for a snapshot, following the selected
+// transaction to where it's now shown
+func renderContent(cfg *Config, snapshot Snapshot) Content {
+	var selected string
+	if sel := getSelection(); sel.Hash != "" {
+		records := displayedTransactions(
+			cfg,
+			filterTransactions(snapshot.Records, getFilterState().filters()),
+		)
+		sel = reselect(records, sel)
+		setSelection(sel)
+		if sel.Hash != "" {
+			selected = shownHash(cfg, sel.Hash)
+		}
+	}
+	main, table := renderSnapshot(cfg, snapshot)
+	return Content{
+		Main:     main,
+		Table:    table,
+		Watch:    RenderWatch(cfg, snapshot),
+		Selected: selected,
+	}
+}
+
+// Snapshot source for refreshes, replaceable for testing
+var snapshotSource = GetSnapshot
+
+// Reads the mempool from the node, or the bundled sample in demo mode, or
+// a generated one with fake data
+func GetSnapshot(cfg *Config, errorChan chan error) (Snapshot, error) {
+	if cfg.App.FakeData {
+		return FakeSnapshot(fakeDataParamsFromConfig(cfg), time.Now()), nil
+	}
+	if cfg.App.Demo {
+		snapshot, err := GetDemoSnapshot()
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("failed to load demo data: %w", err)
+		}
+		return snapshot, nil
+	}
+	mempoolMutex.Lock()
+	defer mempoolMutex.Unlock()
+	oConn, err := nodeConn.Get(errorChan)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to connect to node: %w", err)
+	}
+	var syncWarning string
+	tipSlot, tipErr := uint64(0), errTipNotQueried
+	if needsTip(cfg) {
+		tipSlot, tipErr = queryTipSlot(oConn.LocalStateQuery().Client)
+		if tipErr != nil {
+			log.Printf("failed to query chain tip: %s", tipErr)
+		} else {
+			syncWarning = GetSyncWarning(cfg, tipSlot)
 		}
+	}
+	fetch, err := fetchSnapshot(oConn.LocalTxMonitor().Client)
+	if err != nil {
+		nodeConn.Close()
+		return Snapshot{}, fmt.Errorf("failed to acquire mempool: %w", err)
+	}
+	if fetch.ReleaseErr != nil {
+		log.Printf("failed to release mempool snapshot: %s", fetch.ReleaseErr)
+	}
+	if !fetch.ok() {
+		// Dial again next refresh in case the connection is broken
+		nodeConn.Close()
+	}
+	snapshot := NewSnapshot(
+		fetch.Sizes,
+		fetch.SizesErr,
+		fetch.Txs,
+		fetch.TxsErr,
+	)
+	snapshot.Warning = syncWarning
+	if tipErr == nil && cfg.App.ShowExpiry {
+		markExpiry(snapshot.Records, tipSlot)
+	}
+	return snapshot, nil
+}
+
+func GetFooter() string {
+	var sb strings.Builder
+	key := keyTag()
+	sb.WriteString(" " + key + "(esc/q)[white] Quit")
+	sb.WriteString(" | " + key + "(p)[white] Pause")
+	if paused.Load() {
+		sb.WriteString(" " + key + "(paused)[white]")
+	}
+	sb.WriteString(
+		fmt.Sprintf(
+			" | %s(+/-)[white] Refresh: [blue]%s[white]",
+			key,
+			getRefreshInterval(),
+		),
+	)
+	sortBy := getSortBy()
+	if sortBy == "time" && getTimeOrder() == "oldest" {
+		sortBy = "time (oldest first)"
+	}
+	sb.WriteString(
+		fmt.Sprintf(" | %s(s/o)[white] Sort: [blue]%s[white]", key, sortBy),
+	)
+	sb.WriteString(" | " + key + "(u)[white] Unlabeled only")
+	if getFilterState().UnlabeledOnly {
+		sb.WriteString(" " + key + "(on)[white]")
+	}
+	sb.WriteString(
+		fmt.Sprintf(
+			" | %s(f/n/N)[white] Label: [blue]%s[white]",
+			key,
+			labelFilterName(getFilterState()),
+		),
+	)
+	sb.WriteString(" | " + key + "(0)[white] Reset")
+	sb.WriteString(" | " + key + "(t)[white] Times")
+	if updated := getLastSnapshot().Time; !updated.IsZero() {
 		sb.WriteString(
 			fmt.Sprintf(
-				" [white]%-10d %-"+spaces+"s [blue]%s[white]\n",
-				size,
-				icon,
-				tx.Hash(),
+				" | Updated: [blue]%s[white]",
+				formatTimestamp(updated, time.Now(), relativeTimes.Load()),
 			),
 		)
 	}
-	return fmt.Sprint(sb.String())
+	sb.WriteString(" | " + key + "(.)[white] Go to")
+	if alert := largeTxAlerts.Flash(time.Now()); alert != "" {
+		sb.WriteString(" | " + warningTag() + tview.Escape(alert) + "[white]")
+	}
+	sb.WriteString(
+		fmt.Sprintf(
+			" | Uptime: [blue]%s[white] | Refreshes: [blue]%d[white]",
+			formatUptime(time.Since(startTime)),
+			refreshCount.Load(),
+		),
+	)
+	return fmt.Sprintln(sb.String())
+}
+
+// Formats a duration as [Nd ]HH:MM:SS
+func formatUptime(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	secs := int64(d / time.Second)
+	days := secs / 86400
+	hours := (secs % 86400) / 3600
+	minutes := (secs % 3600) / 60
+	seconds := secs % 60
+	if days > 0 {
+		return fmt.Sprintf("%dd %02d:%02d:%02d", days, hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
+}
+
+// Sets up what classifying and rendering transactions depend on from cfg
+// and the files it names, so the UI, --output and the subcommands all show
+// the same sort, labels, wallets, theme and times
+func initFromConfig(cfg *Config) error {
+	if err := resetViewState(cfg); err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	relativeTimes.Store(cfg.App.TimeFormat == "relative")
+	themeName := cfg.App.Theme
+	if themeName == "" {
+		themeName = autoTheme(DetectTerminalCaps())
+	}
+	activeTheme = themes[themeName]
+	txSampler.size = int(cfg.App.SampleSize)
+	if cfg.App.LabelsFile != "" {
+		labels, err := LoadLabels(cfg.App.LabelsFile)
+		if err != nil {
+			return fmt.Errorf("failed to load labels: %w", err)
+		}
+		userLabels = labels
+		knownLabels = newLabelTable(defaultKnownLabels, userLabels)
+	}
+	if cfg.App.WalletsFile != "" {
+		wallets, err := LoadWallets(cfg.App.WalletsFile)
+		if err != nil {
+			return fmt.Errorf("failed to load wallets: %w", err)
+		}
+		knownWallets = wallets
+	}
+	return nil
+}
+
+// Flags of the dump and diff subcommands, which follow the command name, as
+// in txtop diff -demo report.txt
+type subcommandFlags struct {
+	demo bool
+	// Arguments left after the flags
+	args []string
+}
+
+// Parses the flags of the name subcommand from args, writing errors and
+// usage to output
+func parseSubcommand(
+	name string,
+	args []string,
+	output io.Writer,
+) (subcommandFlags, error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(output)
+	demo := flags.Bool(
+		"demo",
+		false,
+		"read bundled sample data instead of connecting to a node",
+	)
+	if err := flags.Parse(args); err != nil {
+		return subcommandFlags{}, err
+	}
+	return subcommandFlags{demo: *demo, args: flags.Args()}, nil
 }
 
 func main() {
+	demo := flag.Bool(
+		"demo",
+		false,
+		"render bundled sample data instead of connecting to a node",
+	)
+	diag := flag.Bool(
+		"diag",
+		false,
+		"print detected terminal capabilities and exit",
+	)
+	output := flag.String(
+		"output",
+		"",
+		"print the mempool once as json or csv and exit, without the UI",
+	)
+	flag.Parse()
+	if *diag {
+		fmt.Println("txtop", GetVersionString())
+		fmt.Print(DetectTerminalCaps())
+		os.Exit(0)
+	}
+	if *output != "" && !slices.Contains(outputFormats, *output) {
+		fmt.Printf(
+			"invalid --output: %q (expected one of: %s)\n",
+			*output,
+			strings.Join(outputFormats, ", "),
+		)
+		os.Exit(1)
+	}
 	cfg, err := LoadConfig()
 	if err != nil {
-		fmt.Printf("failed to load config: %s", err)
+		fmt.Printf("failed to load config: %s\n", err)
 		os.Exit(1)
 	}
+	if err := initFromConfig(cfg); err != nil {
+		fmt.Printf("%s\n", err)
+		os.Exit(1)
+	}
+	if *output != "" {
+		if *demo {
+			cfg.App.Demo = true
+		}
+		os.Exit(runExport(cfg, *output))
+	}
+	switch flag.Arg(0) {
+	case "":
+	case "dump", "diff":
+		sub, err := parseSubcommand(flag.Arg(0), flag.Args()[1:], os.Stderr)
+		if err != nil {
+			os.Exit(2)
+		}
+		if *demo || sub.demo {
+			cfg.App.Demo = true
+		}
+		if flag.Arg(0) == "dump" {
+			os.Exit(runDump(cfg, os.Stdout))
+		}
+		var path string
+		if len(sub.args) > 0 {
+			path = sub.args[0]
+		}
+		os.Exit(runDiff(cfg, path))
+	default:
+		fmt.Printf(
+			"unknown command: %q (expected: dump or diff)\n",
+			flag.Arg(0),
+		)
+		os.Exit(1)
+	}
+	// Log to a buffer while the UI owns the terminal, and print it on exit
+	log.SetOutput(logBuffer)
+	defer func() {
+		fmt.Print(exitLogDump(cfg, logBuffer))
+		if cfg.App.ExitSummary {
+			fmt.Print(runSummary(currentRunStats()))
+		}
+	}()
+	if *demo {
+		cfg.App.Demo = true
+	}
+	setRefreshInterval(cfg.App.Refresh.Duration())
+	redraw = NewRedrawThrottle(cfg.App.MaxFPS, queueUpdateDraw)
+	screenDimmer = NewDimmer(
+		time.Second*time.Duration(cfg.App.DimAfter),
+		time.Now(),
+	)
+	txLinger.enabled = cfg.App.Linger
+	labelStats = NewWindowedStats(
+		time.Second * time.Duration(cfg.App.StatsWindow),
+	)
+	if cfg.App.FIFOPath != "" {
+		writer, err := NewFIFOWriter(cfg.App.FIFOPath)
+		if err != nil {
+			fmt.Printf("failed to set up FIFO: %s\n", err)
+			os.Exit(1)
+		}
+		fifoWriter = writer
+	}
+	largeTxAlerts = NewLargeTxAlerter(int(cfg.App.LargeTxAlert))
+	if cfg.App.ArchiveInterval > 0 {
+		archiver, err := NewSnapshotArchiver(
+			cfg.App.ArchiveDir,
+			time.Minute*time.Duration(cfg.App.ArchiveInterval),
+			int(cfg.App.ArchiveKeep),
+		)
+		if err != nil {
+			fmt.Printf("failed to set up archiving: %s\n", err)
+			os.Exit(1)
+		}
+		snapshotArchiver = archiver
+	}
+	if cfg.App.StatsdAddress != "" {
+		client, err := NewStatsdClient(cfg.App.StatsdAddress)
+		if err != nil {
+			fmt.Printf("failed to set up StatsD: %s\n", err)
+			os.Exit(1)
+		}
+		statsdClient = client
+	}
+	connBreaker = NewCircuitBreaker(
+		int(cfg.App.Retries),
+		time.Second*time.Duration(cfg.App.RetryBackoff),
+	)
+	recentlyCleared = NewClearedTracker(
+		time.Second*time.Duration(cfg.App.RecentlyCleared),
+		maxRecentlyCleared,
+	)
+	if cfg.App.SeenFile != "" {
+		if err := txAges.Load(cfg.App.SeenFile); err != nil {
+			log.Printf("failed to load seen transactions: %s", err)
+		}
+	}
+	screen, err := startScreen(tcell.NewScreen)
+	if err != nil {
+		fmt.Fprintf(
+			os.Stderr,
+			"failed to start the UI, printing the mempool instead: %s\n",
+			err,
+		)
+		done := make(chan struct{})
+		interrupts := make(chan os.Signal, 1)
+		signal.Notify(interrupts, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-interrupts
+			close(done)
+		}()
+		runFollow(cfg, os.Stdout, done)
+		shutdown(cfg)
+		return
+	}
+	app.SetScreen(screen)
 	// text.SetBorder(true)
 	errorChan := make(chan error)
 	go func() {
 		for {
 			err := <-errorChan
-			text.SetText(fmt.Sprintf(" [red]ERROR: async: %s", err))
+			connectionErrors.Add(1)
+			redraw.Request("content", func() {
+				text.SetText(
+					fmt.Sprintf(" %sERROR: async: %s", errorTag(), err),
+				)
+				// Force the next refresh to redraw the content
+				displayed.Main = ""
+			})
 		}
 	}()
-	oConn, err := GetConnection(errorChan)
-	if err != nil {
-		text.SetText(fmt.Sprintf(" [red]failed to connect to node: %s", err))
-	} else {
-		text.SetText(fmt.Sprintf("%s\n%s",
-			GetSizes(oConn),
-			GetTransactions(oConn),
-		))
-	}
-	headerText.SetText(fmt.Sprintln(" > txtop -", GetVersionString()))
-	footerText.SetText(
-		fmt.Sprintln(" [yellow](esc/q)[white] Quit | [yellow](p)[white] Pause"),
-	)
-	legendText.SetText(
-		fmt.Sprintf(" Legend: [white]%s\n %s\n %s",
-			fmt.Sprintf("%12s %12s %12s %12s %12s %12s",
-				"🏹 Dexhunter",
-				"🚰 DripDropz",
-				"👁️ Indigo",
-				"🦛 JPGstore",
-				"💧 Liqwid",
-				"🐱 Minswap",
-			),
-			// Text formatting the wrong way for the win
-			fmt.Sprintf("%17s %15s %12s %10s %18s",
-				"🅾️ Optim",
-				"🌈 Spectrum",
-				"🍨 Sundae",
-				"🦭 SealVM",
-				"🦸 Wingriders",
-			),
-			fmt.Sprintf("%18s %9s",
-				"🥩 Staking",
-				"🏊 SPOs",
-			),
-		),
-	)
+	immediate := showInitialContent(cfg, errorChan)
+	setupUI(cfg)
+	startAPI(cfg)
+	startRefreshLoop(cfg, errorChan, immediate)
+	startSizesLoop(cfg, errorChan)
+	startHealthLoop(cfg)
+
+	// Hotkeys are handled by input capture, which only sees key events, so
+	// mouse events go straight to the views for scrolling and focus
+	app.SetRoot(pages, true).EnableMouse(cfg.App.Mouse)
+	if err := app.Run(); err != nil {
+		panic(err)
+	}
+	shutdown(cfg)
+}
+
+// Closes the node connection and saves what should outlive this run
+func shutdown(cfg *Config) {
+	nodeConn.Close()
+	if cfg.App.SeenFile != "" {
+		if err := txAges.Save(cfg.App.SeenFile); err != nil {
+			log.Printf("failed to save seen transactions: %s", err)
+		}
+	}
+}
+
+// Performs the first fetch synchronously so the UI starts populated. This
+// runs before the tview event loop starts, so it sets views directly
+func initializeData(cfg *Config, errorChan chan error) {
+	initial := GetContent(cfg, errorChan)
+	updateUI(initial)
+}
+
+// Shows the content before the UI starts. With SKIP_INITIAL_FETCH it only
+// shows that it's connecting, and returns true so the refresh loop does the
+// first fetch right away instead
+func showInitialContent(cfg *Config, errorChan chan error) bool {
+	if cfg.App.SkipInitialFetch {
+		text.SetText(" " + warningTag() + "connecting…")
+		return true
+	}
+	initializeData(cfg, errorChan)
+	return false
+}
+
+func renderHeader(cfg *Config) string {
+	var sb strings.Builder
+	sb.WriteString(" > txtop - " + GetVersionString())
+	if cfg.App.Title != "" {
+		sb.WriteString(" | [white]" + tview.Escape(cfg.App.Title) + "[green]")
+	}
+	if cfg.App.FakeData {
+		sb.WriteString(" " + warningTag() + "(fake data)[white]")
+	} else if cfg.App.Demo {
+		sb.WriteString(" " + warningTag() + "(demo data)[white]")
+	}
+	if cfg.App.HealthCmd != "" {
+		sb.WriteString(" | Health: " + getHealthStatus().String())
+	}
+	return fmt.Sprintln(sb.String())
+}
+
+func setupUI(cfg *Config) {
+	headerText.SetText(renderHeader(cfg))
+	footerText.SetText(GetFooter())
+	legendWidth := DetectTerminalCaps().Width
+	legendRows := showLegend(cfg, legendWidth)
+	showWatch := cfg.App.ShowWatchPane
+	layoutMain(flex, showWatch, legendRows)
+	// Reflow the legend when the terminal is resized
+	app.SetBeforeDrawFunc(func(screen tcell.Screen) bool {
+		width, _ := screen.Size()
+		if width != legendWidth {
+			legendWidth = width
+			legendRows = showLegend(cfg, legendWidth)
+			layoutMain(flex, showWatch, legendRows)
+		}
+		return false
+	})
+	text.SetMouseCapture(selectOnClick(cfg, text))
+	flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		screenDimmer.Activity(time.Now())
+		if dimmed, changed := screenDimmer.Update(time.Now()); changed {
+			applyDim(dimmed)
+		}
+		if event.Key() == tcell.KeyRune &&
+			!allowKey(keyDebouncer, event.Rune(), time.Now()) {
+			return event
+		}
+		if event.Rune() == 112 { // p
+			paused.Store(!paused.Load())
+			footerText.Clear()
+			footerText.SetText(GetFooter())
+		}
+		if event.Rune() == 43 || event.Rune() == 45 { // +, -
+			step := 1
+			if event.Rune() == 45 {
+				step = -1
+			}
+			stepRefreshInterval(step)
+			footerText.Clear()
+			footerText.SetText(GetFooter())
+		}
+		if event.Rune() == 115 { // s
+			toggleSortBy()
+			rerenderFromCache(cfg)
+			footerText.Clear()
+			footerText.SetText(GetFooter())
+		}
+		if event.Rune() == 111 { // o
+			toggleTimeOrder()
+			rerenderFromCache(cfg)
+			footerText.Clear()
+			footerText.SetText(GetFooter())
+		}
+		if event.Rune() == 117 { // u
+			toggleUnlabeledOnly()
+			rerenderFromCache(cfg)
+			footerText.Clear()
+			footerText.SetText(GetFooter())
+		}
+		if event.Rune() == 102 { // f
+			cycleLabelFilter(legendEntries(cfg))
+			rerenderFromCache(cfg)
+			footerText.Clear()
+			footerText.SetText(GetFooter())
+		}
+		if event.Rune() == 110 || event.Rune() == 78 { // n, N
+			step := 1
+			if event.Rune() == 78 {
+				step = -1
+			}
+			stepLabelFilter(step)
+			rerenderFromCache(cfg)
+			footerText.Clear()
+			footerText.SetText(GetFooter())
+		}
+		if event.Rune() == 48 { // 0
+			if err := resetViewState(cfg); err != nil {
+				log.Printf("failed to reset view: %s", err)
+			}
+			rerenderFromCache(cfg)
+			footerText.Clear()
+			footerText.SetText(GetFooter())
+		}
+		if event.Rune() == 46 { // .
+			showJumpPrompt(cfg)
+			return nil
+		}
+		if event.Rune() == 100 && cfg.App.Debug { // d
+			toggleDebugPage()
+			return nil
+		}
+		if event.Rune() == 116 { // t
+			toggleTimeFormat()
+			rerenderFromCache(cfg)
+			footerText.Clear()
+			footerText.SetText(GetFooter())
+		}
+		if event.Rune() == 119 { // w
+			showWatch = !showWatch
+			layoutMain(flex, showWatch, legendRows)
+		}
+		if event.Rune() == 113 || event.Key() == tcell.KeyEscape { // q
+			app.Stop()
+		}
+		return event
+	})
+	pages.AddPage("Main", flex, true, true)
+}
+
+// Renders the last snapshot again after the sort or filters change, so the
+// view matches right away instead of at the next refresh, which never comes
+// while paused. Runs on the tview event loop
+func rerenderFromCache(cfg *Config) {
+	snapshot := getLastSnapshot()
+	if snapshot.Time.IsZero() {
+		return
+	}
+	current := renderContent(cfg, snapshot)
+	updateUI(current)
+}
+
+// Returns the sort and filters to the configured defaults
+func resetViewState(cfg *Config) error {
+	if err := setSortBy(cfg.App.SortBy); err != nil {
+		return err
+	}
+	if err := setTimeOrder(cfg.App.TimeOrder); err != nil {
+		return err
+	}
+	setFilterState(filterState{UnlabeledOnly: cfg.App.UnlabeledOnly})
+	return nil
+}
+
+// Lays out the legend for width cells, returning the number of rows it
+// needs
+func showLegend(cfg *Config, width int) int {
+	legend, rows := renderLegend(legendEntries(cfg), width)
+	legendText.SetText(legend)
+	return rows
+}
+
+// Arranges the main page, splitting the transactions into the full mempool
+// and a pane of watched transactions when showWatch is set
+func layoutMain(flex *tview.Flex, showWatch bool, legendRows int) {
+	flex.Clear()
 	flex.SetDirection(tview.FlexRow).
 		AddItem(headerText,
 			1,
@@ -429,65 +1651,180 @@ func main() {
 		AddItem(text,
 			0,
 			6,
-			true).
-		AddItem(legendText,
-			3,
+			true)
+	if showWatch {
+		flex.AddItem(watchText,
 			0,
-			false).
+			3,
+			false)
+	}
+	flex.AddItem(legendText,
+		legendRows,
+		0,
+		false).
 		AddItem(footerText,
 			2,
 			0,
 			false)
-	flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		if event.Rune() == 112 { // p
-			paused = !paused
-			footerText.Clear()
-			if paused {
-				footerText.SetText(
-					fmt.Sprintln(
-						" [yellow](esc/q)[white] Quit | [yellow](p)[white] Pause [yellow](paused)",
-					),
-				)
-				return event
+}
+
+// Applies a refresh to the views, only touching those whose text changed.
+// Views must only be changed from the tview event loop, so this should be
+// run through app.QueueUpdateDraw
+func updateUI(current Content) {
+	if current.Main != "" && current.Main != displayed.Main {
+		text.Clear()
+		text.SetText(current.Main)
+		displayed.Main = current.Main
+		displayed.Table = current.Table
+		// Keep the selected transaction in view wherever it moved to
+		row := -1
+		if current.Selected != "" {
+			row = lineContaining(current.Main, current.Selected, current.Table)
+		}
+		if row >= 0 {
+			text.ScrollTo(row, 0)
+		}
+	}
+	if current.Watch != displayed.Watch {
+		watchText.Clear()
+		watchText.SetText(current.Watch)
+		displayed.Watch = current.Watch
+	}
+}
+
+// Refreshes the content every interval. When immediate is set, the first
+// refresh happens right away instead of after the first interval
+func startRefreshLoop(cfg *Config, errorChan chan error, immediate bool) {
+	interval := NewAdaptiveInterval(
+		cfg.App.Refresh.Duration(),
+		cfg.App.IdleMaxRefresh.Duration(),
+	)
+	go runRefreshLoop(
+		interval,
+		time.After,
+		nil,
+		immediate,
+		newRefresh(cfg, errorChan, interval),
+	)
+}
+
+// Returns the refresh run by the refresh loop, which fetches the content and
+// queues it to be shown
+func newRefresh(
+	cfg *Config,
+	errorChan chan error,
+	interval *AdaptiveInterval,
+) func() (int, bool) {
+	return func() (int, bool) {
+		// The refresh keys may have changed the interval since the last one
+		interval.SetBase(getRefreshInterval())
+		defer func() {
+			if dimmed, changed := screenDimmer.Update(time.Now()); changed {
+				redraw.Request("dim", func() {
+					applyDim(dimmed)
+				})
 			}
-			footerText.SetText(
-				fmt.Sprintln(
-					" [yellow](esc/q)[white] Quit | [yellow](p)[white] Pause",
-				),
-			)
+		}()
+		if paused.Load() {
+			if nodeConn.CloseIfIdle(
+				time.Now(),
+				time.Second*time.Duration(cfg.App.IdleTimeout),
+			) {
+				log.Print("disconnected from idle node connection")
+			}
+			// only keep the uptime current
+			footer := GetFooter()
+			redraw.Request("footer", func() {
+				footerText.SetText(footer)
+			})
+			return 0, false
 		}
-		if event.Rune() == 113 || event.Key() == tcell.KeyEscape { // q
-			app.Stop()
+		fetchStart := time.Now()
+		tmpContent := fetchOrError(
+			func() Content { return GetContent(cfg, errorChan) },
+			cfg.App.RecoverPanics,
+		)
+		// The last snapshot is only from this refresh if it worked
+		txCount := -1
+		snapshot := getLastSnapshot()
+		if !snapshot.Time.Before(fetchStart) {
+			txCount = snapshot.Drained
+			screenDimmer.Observe(snapshot.Hash, time.Now())
 		}
-		return event
-	})
-	pages.AddPage("Main", flex, true, true)
-	go func(cfg *Config) {
-		for {
-			if paused {
-				// do nothing
-			} else {
-				time.Sleep(time.Second * time.Duration(cfg.App.Refresh))
-				oConn, err := GetConnection(errorChan)
-				if err != nil {
-					text.Clear()
-					text.SetText(fmt.Sprintf(" [red]failed to connect to node: %s", err))
-				} else {
-					tmpText := fmt.Sprintf("%s\n%s",
-						GetSizes(oConn),
-						GetTransactions(oConn),
-					)
-					if tmpText != "" && tmpText != content {
-						content = tmpText
-						text.Clear()
-						text.SetText(content)
-					}
-				}
+		refreshCount.Add(1)
+		redraw.Request("content", func() {
+			updateUI(tmpContent)
+		})
+		footer := GetFooter()
+		redraw.Request("footer", func() {
+			footerText.SetText(footer)
+		})
+		return txCount, true
+	}
+}
+
+// Calls refresh after each wait from interval until done is closed, or
+// right away first when immediate is set. Waits come from after, which is
+// time.After outside of tests. Refresh returns the transaction count the
+// next interval depends on, or false when it didn't fetch, such as while
+// paused, which keeps the interval as is
+func runRefreshLoop(
+	interval *AdaptiveInterval,
+	after func(time.Duration) <-chan time.Time,
+	done <-chan struct{},
+	immediate bool,
+	refresh func() (int, bool),
+) {
+	wait := interval.Next(-1)
+	for {
+		if !immediate {
+			select {
+			case <-done:
+				return
+			case <-after(wait):
 			}
 		}
-	}(cfg)
+		immediate = false
+		if txCount, fetched := refresh(); fetched {
+			wait = interval.Next(txCount)
+		}
+	}
+}
 
-	if err := app.SetRoot(pages, true).EnableMouse(false).Run(); err != nil {
-		panic(err)
+// Runs fetch, turning a panic into an error so a bug in one refresh doesn't
+// silently stop the refresh loop. With recoverPanics unset, panics propagate
+func safeFetch(
+	fetch func() Content,
+	recoverPanics bool,
+) (_ Content, err error) {
+	if recoverPanics {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf(
+					"recovered from panic during refresh: %v\n%s",
+					r,
+					debug.Stack(),
+				)
+				err = fmt.Errorf("panic during refresh: %v", r)
+			}
+		}()
+	}
+	return fetch(), nil
+}
+
+// Runs fetch like safeFetch, showing a recovered panic in place of the
+// content so the next refresh replaces it
+func fetchOrError(fetch func() Content, recoverPanics bool) Content {
+	content, err := safeFetch(fetch, recoverPanics)
+	if err != nil {
+		return Content{
+			Main: fmt.Sprintf(
+				" %sERROR: %s (details are logged on exit)",
+				errorTag(),
+				err,
+			),
+		}
 	}
+	return content
 }
@@ -0,0 +1,639 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	lcommon "github.com/blinklabs-io/gouroboros/ledger/common"
+)
+
+func TestFormatSizes(t *testing.T) {
+	sizes := MempoolSizes{Capacity: 1000, Size: 300, NumberOfTxs: 3}
+	tests := []struct {
+		name         string
+		count        int
+		wantMismatch bool
+	}{
+		{"matching", 3, false},
+		{"mismatched", 2, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			text := FormatSizes(sizes, test.count, "bytes")
+			mismatch := strings.Contains(text, "node reported")
+			if mismatch != test.wantMismatch {
+				t.Errorf("got %q", text)
+			}
+		})
+	}
+}
+
+func TestRenderSnapshotPolledSizes(t *testing.T) {
+	snapshot := Snapshot{
+		Sizes:   MempoolSizes{Capacity: 1000, NumberOfTxs: 2},
+		Drained: 2,
+	}
+	setLastSnapshot(snapshot)
+	t.Cleanup(func() { setLastSnapshot(Snapshot{}) })
+	polled := updateLastSnapshotSizes(
+		MempoolSizes{Capacity: 1000, NumberOfTxs: 5},
+	)
+	if got := polled.transactionCount(); got != 5 {
+		t.Errorf("got count %d, want 5", got)
+	}
+	text := RenderSnapshot(&Config{}, polled)
+	if strings.Contains(text, "node reported") {
+		t.Errorf("mismatch noted after a sizes poll: %q", text)
+	}
+	if !strings.Contains(text, "Transactions: [blue]5 ") {
+		t.Errorf("polled count not shown: %q", text)
+	}
+}
+
+// Returns a copy of the default config for tests to adjust
+func testConfig() *Config {
+	cfg := *globalConfig
+	return &cfg
+}
+
+// Restores what initFromConfig sets once the test is done
+func keepInitState(t *testing.T) {
+	t.Helper()
+	sortBy, timeOrder := getSortBy(), getTimeOrder()
+	filter := getFilterState()
+	relative := relativeTimes.Load()
+	theme := activeTheme
+	sampleSize := txSampler.size
+	labels, user := knownLabels, userLabels
+	wallets := knownWallets
+	t.Cleanup(func() {
+		_ = setSortBy(sortBy)
+		_ = setTimeOrder(timeOrder)
+		setFilterState(filter)
+		relativeTimes.Store(relative)
+		activeTheme = theme
+		txSampler.size = sampleSize
+		knownLabels, userLabels = labels, user
+		knownWallets = wallets
+	})
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(cfg *Config)
+		wantErr bool
+	}{
+		{"defaults", func(cfg *Config) {}, false},
+		{
+			"normalizes case",
+			func(cfg *Config) {
+				cfg.App.SortBy = " TIME "
+				cfg.App.Theme = "Protanopia"
+			},
+			false,
+		},
+		{"bad sort", func(cfg *Config) { cfg.App.SortBy = "fee" }, true},
+		{
+			"bad index mode",
+			func(cfg *Config) { cfg.App.IndexMode = "row" },
+			true,
+		},
+		{
+			"page numbering without a page size",
+			func(cfg *Config) {
+				cfg.App.IndexMode = "page"
+				cfg.App.PageSize = 0
+			},
+			true,
+		},
+		{
+			"bad capacity unit",
+			func(cfg *Config) { cfg.App.CapacityUnit = "gb" },
+			true,
+		},
+		{"bad theme", func(cfg *Config) { cfg.App.Theme = "sepia" }, true},
+		{
+			"sizes refresh below the minimum",
+			func(cfg *Config) {
+				cfg.App.SizesRefresh = RefreshInterval(50 * time.Millisecond)
+			},
+			true,
+		},
+		{
+			"sizes refresh between refreshes",
+			func(cfg *Config) {
+				cfg.App.SizesRefresh = RefreshInterval(500 * time.Millisecond)
+			},
+			false,
+		},
+		{
+			"idle max refresh below refresh",
+			func(cfg *Config) {
+				cfg.App.Refresh = RefreshInterval(3 * time.Second)
+				cfg.App.IdleMaxRefresh = RefreshInterval(time.Second)
+			},
+			true,
+		},
+		{
+			"idle max refresh equal to refresh",
+			func(cfg *Config) {
+				cfg.App.Refresh = RefreshInterval(3 * time.Second)
+				cfg.App.IdleMaxRefresh = RefreshInterval(3 * time.Second)
+			},
+			false,
+		},
+		{
+			"idle max refresh below the raised refresh",
+			func(cfg *Config) {
+				cfg.App.Refresh = 0
+				cfg.App.IdleMaxRefresh = RefreshInterval(50 * time.Millisecond)
+			},
+			true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := testConfig()
+			test.modify(cfg)
+			err := cfg.Validate()
+			if (err != nil) != test.wantErr {
+				t.Errorf("got error %v, want error %t", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigValidateClampsRefresh(t *testing.T) {
+	cfg := testConfig()
+	cfg.App.Refresh = 0
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cfg.App.Refresh != minRefresh {
+		t.Errorf("got refresh %s, want %s", cfg.App.Refresh, minRefresh)
+	}
+}
+
+func TestRunRefreshLoopZeroInterval(t *testing.T) {
+	done := make(chan struct{})
+	var waits []time.Duration
+	after := func(wait time.Duration) <-chan time.Time {
+		waits = append(waits, wait)
+		ticks := make(chan time.Time, 1)
+		select {
+		case <-done:
+			// Never fires, so the loop sees done
+		default:
+			ticks <- time.Now()
+		}
+		return ticks
+	}
+	var refreshes int
+	runRefreshLoop(
+		NewAdaptiveInterval(0, 0),
+		after,
+		done,
+		false,
+		func() (int, bool) {
+			refreshes++
+			if refreshes == 5 {
+				close(done)
+			}
+			return 0, true
+		},
+	)
+	if refreshes != 5 {
+		t.Errorf("got %d refreshes, want 5", refreshes)
+	}
+	for _, wait := range waits {
+		if wait < minRefreshInterval {
+			t.Errorf(
+				"waited %s between refreshes, want at least %s",
+				wait,
+				minRefreshInterval,
+			)
+		}
+	}
+}
+
+func TestRefreshLoopRecoversFromPanic(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+	done := make(chan struct{})
+	after := func(time.Duration) <-chan time.Time {
+		ticks := make(chan time.Time, 1)
+		select {
+		case <-done:
+			// Never fires, so the loop sees done
+		default:
+			ticks <- time.Now()
+		}
+		return ticks
+	}
+	var fetches int
+	fetch := func() Content {
+		fetches++
+		if fetches == 1 {
+			panic("decode failed")
+		}
+		return Content{Main: "refreshed"}
+	}
+	var rendered []string
+	runRefreshLoop(
+		NewAdaptiveInterval(0, 0),
+		after,
+		done,
+		true,
+		func() (int, bool) {
+			rendered = append(rendered, fetchOrError(fetch, true).Main)
+			if len(rendered) == 3 {
+				close(done)
+			}
+			return 0, true
+		},
+	)
+	if len(rendered) != 3 {
+		t.Fatalf("got %d refreshes, want 3", len(rendered))
+	}
+	if !strings.Contains(rendered[0], "panic during refresh: decode failed") {
+		t.Errorf("first refresh rendered %q, want the panic", rendered[0])
+	}
+	for _, main := range rendered[1:] {
+		if main != "refreshed" {
+			t.Errorf("later refresh rendered %q, want %q", main, "refreshed")
+		}
+	}
+	if !strings.Contains(
+		logged.String(),
+		"recovered from panic during refresh: decode failed",
+	) {
+		t.Errorf("panic wasn't logged, got %q", logged.String())
+	}
+}
+
+func TestFormatUptime(t *testing.T) {
+	tests := []struct {
+		name string
+		d    time.Duration
+		want string
+	}{
+		{"zero", 0, "00:00:00"},
+		{"negative", -time.Second, "00:00:00"},
+		{"seconds", 59 * time.Second, "00:00:59"},
+		{"minute", time.Minute, "00:01:00"},
+		{"hour", time.Hour, "01:00:00"},
+		{"just under a day", 24*time.Hour - time.Second, "23:59:59"},
+		{"day", 24 * time.Hour, "1d 00:00:00"},
+		{
+			"over a day",
+			50*time.Hour + 3*time.Minute + 4*time.Second,
+			"2d 02:03:04",
+		},
+		{"fraction of a second", 1500 * time.Millisecond, "00:00:01"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := formatUptime(test.d); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRerenderFromCacheDoesNotFetch(t *testing.T) {
+	defer func(saved func(*Config, chan error) (Snapshot, error)) {
+		snapshotSource = saved
+	}(snapshotSource)
+	defer setLastSnapshot(getLastSnapshot())
+	var fetches int
+	snapshotSource = func(*Config, chan error) (Snapshot, error) {
+		fetches++
+		return GetDemoSnapshot()
+	}
+	cfg := testConfig()
+	GetContent(cfg, make(chan error, 1))
+	if fetches != 1 {
+		t.Fatalf("got %d fetches from a refresh, want 1", fetches)
+	}
+	fetches = 0
+	displayed = Content{}
+	rerenderFromCache(cfg)
+	if fetches != 0 {
+		t.Errorf("got %d fetches from a rerender, want 0", fetches)
+	}
+	if displayed.Main == "" {
+		t.Error("rerender didn't render the cached snapshot")
+	}
+}
+
+func TestSkipInitialFetch(t *testing.T) {
+	defer func(saved func(*Config, chan error) (Snapshot, error)) {
+		snapshotSource = saved
+	}(snapshotSource)
+	defer func(saved *RedrawThrottle) { redraw = saved }(redraw)
+	defer setLastSnapshot(getLastSnapshot())
+	defer func(saved Content) { displayed = saved }(displayed)
+	var fetches int
+	snapshotSource = func(*Config, chan error) (Snapshot, error) {
+		fetches++
+		return GetDemoSnapshot()
+	}
+	redraw = NewRedrawThrottle(0, func(update func()) { update() })
+	tests := []struct {
+		name        string
+		skip        bool
+		wantInitial int
+	}{
+		{"fetch first", false, 1},
+		{"skip first fetch", true, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fetches = 0
+			displayed = Content{}
+			cfg := testConfig()
+			cfg.App.SkipInitialFetch = test.skip
+			errorChan := make(chan error, 1)
+			immediate := showInitialContent(cfg, errorChan)
+			if fetches != test.wantInitial {
+				t.Fatalf(
+					"got %d fetches before the UI, want %d",
+					fetches,
+					test.wantInitial,
+				)
+			}
+			if immediate != test.skip {
+				t.Errorf("got immediate %t, want %t", immediate, test.skip)
+			}
+			connecting := strings.Contains(text.GetText(true), "connecting")
+			if connecting != test.skip {
+				t.Errorf(
+					"got connecting shown %t, want %t",
+					connecting,
+					test.skip,
+				)
+			}
+			done := make(chan struct{})
+			var waits int
+			after := func(time.Duration) <-chan time.Time {
+				waits++
+				ticks := make(chan time.Time, 1)
+				select {
+				case <-done:
+					// Never fires, so the loop sees done
+				default:
+					ticks <- time.Now()
+				}
+				return ticks
+			}
+			interval := NewAdaptiveInterval(cfg.App.Refresh.Duration(), 0)
+			refresh := newRefresh(cfg, errorChan, interval)
+			var refreshes int
+			var waitsBeforeFirst int
+			runRefreshLoop(
+				interval,
+				after,
+				done,
+				immediate,
+				func() (int, bool) {
+					if refreshes == 0 {
+						waitsBeforeFirst = waits
+					}
+					refreshes++
+					txCount, fetched := refresh()
+					if refreshes == 2 {
+						close(done)
+					}
+					return txCount, fetched
+				},
+			)
+			if got := fetches - test.wantInitial; got != 2 {
+				t.Errorf("got %d fetches from refreshes, want 2", got)
+			}
+			if (waitsBeforeFirst > 0) != !test.skip {
+				t.Errorf(
+					"waited %d times before the first refresh",
+					waitsBeforeFirst,
+				)
+			}
+			if shown := text.GetText(true); shown == "" ||
+				strings.Contains(shown, "connecting") {
+				t.Errorf("refreshes didn't show the content: %q", shown)
+			}
+		})
+	}
+}
+
+func TestUniqueAddresses(t *testing.T) {
+	tests := []struct {
+		name string
+		txs  []TxRecord
+		want int
+	}{
+		{"empty", nil, 0},
+		{
+			"distinct",
+			[]TxRecord{
+				{Addresses: []string{"a", "b"}},
+				{Addresses: []string{"c"}},
+			},
+			3,
+		},
+		{
+			"overlapping",
+			[]TxRecord{
+				{Addresses: []string{"a", "b"}},
+				{Addresses: []string{"b", "c"}},
+				{Addresses: []string{"a"}},
+				{},
+			},
+			3,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := uniqueAddresses(test.txs); got != test.want {
+				t.Errorf("got %d addresses, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestOutputAddressesDedupesInOrder(t *testing.T) {
+	tx := fakeTx{
+		outputs: []lcommon.TransactionOutput{
+			testOutput(t, sundaeAddress),
+			testOutput(t, minswapAddress),
+			testOutput(t, sundaeAddress),
+			testOutput(t, minswapAddress),
+		},
+	}
+	got := outputAddresses(tx)
+	want := []string{sundaeAddress, minswapAddress}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestRenderHeaderTitle(t *testing.T) {
+	defaultHeader := " > txtop - " + GetVersionString() + "\n"
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{"unset", "", defaultHeader},
+		{
+			"configured",
+			"Relay A — mainnet",
+			" > txtop - " + GetVersionString() +
+				" | [white]Relay A — mainnet[green]\n",
+		},
+		{
+			"escaped",
+			"relay [a]",
+			" > txtop - " + GetVersionString() +
+				" | [white]relay [a[][green]\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.App.Title = test.title
+			cfg.App.FakeData = false
+			cfg.App.Demo = false
+			cfg.App.HealthCmd = ""
+			if got := renderHeader(cfg); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseSubcommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantDemo bool
+		wantArgs []string
+		wantErr  bool
+	}{
+		{"no flags", nil, false, []string{}, false},
+		{"demo", []string{"-demo"}, true, []string{}, false},
+		{
+			"demo and a file",
+			[]string{"-demo", "report.txt"},
+			true,
+			[]string{"report.txt"},
+			false,
+		},
+		{
+			"file only",
+			[]string{"report.txt"},
+			false,
+			[]string{"report.txt"},
+			false,
+		},
+		{"unknown flag", []string{"-verbose"}, false, nil, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var output bytes.Buffer
+			sub, err := parseSubcommand("diff", test.args, &output)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %t", err, test.wantErr)
+			}
+			if test.wantErr {
+				if !strings.Contains(output.String(), "-demo") {
+					t.Errorf("usage missing from %q", output.String())
+				}
+				return
+			}
+			if sub.demo != test.wantDemo {
+				t.Errorf("got demo %t, want %t", sub.demo, test.wantDemo)
+			}
+			if !slices.Equal(sub.args, test.wantArgs) {
+				t.Errorf("got args %q, want %q", sub.args, test.wantArgs)
+			}
+		})
+	}
+}
+
+func TestUnknownEnvVars(t *testing.T) {
+	environ := []string{
+		"TXTOP_APP_REFERSH=5",
+		"TXTOP_APP_REFRESH=5",
+		"TXTOP_NODE_CARDANO_NODE_SOCKET_PATH=/tmp/socket",
+		"HOME=/root",
+		"REFERSH=5",
+	}
+	got := unknownEnvVars("txtop", testConfig(), environ)
+	if !slices.Equal(got, []string{"TXTOP_APP_REFERSH"}) {
+		t.Errorf("got %v, want only the typo", got)
+	}
+}
+
+func TestCheckUnknownEnv(t *testing.T) {
+	typo := []string{"TXTOP_APP_REFERSH=5", "TXTOP_APP_REFRESH=5"}
+	tests := []struct {
+		name     string
+		mode     string
+		environ  []string
+		wantErr  bool
+		wantWarn bool
+	}{
+		{"ignore", "ignore", typo, false, false},
+		{"default", "", typo, false, false},
+		{"warn", "warn", typo, false, true},
+		{"warn in capitals", "WARN", typo, false, true},
+		{"fail", "fail", typo, true, false},
+		{
+			"fail when clean",
+			"fail",
+			[]string{"TXTOP_APP_REFRESH=5"},
+			false,
+			false,
+		},
+		{"bad mode", "loud", nil, true, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.App.UnknownEnv = test.mode
+			var out strings.Builder
+			err := cfg.checkUnknownEnv(test.environ, &out)
+			if (err != nil) != test.wantErr {
+				t.Errorf("got error %v, want error %t", err, test.wantErr)
+			}
+			if strings.Contains(out.String(), "TXTOP_APP_REFERSH") !=
+				test.wantWarn {
+				t.Errorf("got warnings %q", out.String())
+			}
+			if test.wantErr && test.mode == "fail" &&
+				!strings.Contains(err.Error(), "TXTOP_APP_REFERSH") {
+				t.Errorf("error doesn't name the typo: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+// The parts of the LocalTxMonitor client used to read the mempool
+type mempoolClient interface {
+	Acquire() error
+	GetSizes() (uint32, uint32, uint32, error)
+	NextTx() ([]byte, error)
+	Release() error
+}
+
+// The mempool as read under a single acquisition
+type mempoolFetch struct {
+	Sizes    MempoolSizes
+	SizesErr error
+	Txs      [][]byte
+	TxsErr   error
+	// Error from releasing the acquired mempool snapshot
+	ReleaseErr error
+}
+
+// Reports whether every call worked, so the connection can be reused
+func (f mempoolFetch) ok() bool {
+	return f.SizesErr == nil && f.TxsErr == nil && f.ReleaseErr == nil
+}
+
+// Acquires a snapshot of the mempool, reads its sizes and drains its
+// transactions, so both come from the same mempool rather than one
+// churning in between, and then releases it so the next fetch sees a fresh
+// snapshot. Only failing to acquire is returned as an error; the other
+// calls' errors are kept with what was read. The snapshot isn't released
+// after a failed call, as the connection is likely broken
+func fetchSnapshot(client mempoolClient) (mempoolFetch, error) {
+	if err := client.Acquire(); err != nil {
+		return mempoolFetch{}, err
+	}
+	var fetch mempoolFetch
+	fetch.Sizes, fetch.SizesErr = GetSizes(client)
+	fetch.Txs, fetch.TxsErr = GetTransactions(client)
+	if fetch.SizesErr == nil && fetch.TxsErr == nil {
+		fetch.ReleaseErr = client.Release()
+	}
+	return fetch, nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+// A mempool whose contents change with every acquisition, recording the
+// calls made against it
+type fakeMempoolClient struct {
+	// Transactions in the mempool for each acquisition in turn
+	mempools   [][][]byte
+	acquires   int
+	releases   int
+	acquired   [][]byte
+	next       int
+	acquireErr error
+	nextTxErr  error
+}
+
+func (c *fakeMempoolClient) Acquire() error {
+	if c.acquireErr != nil {
+		return c.acquireErr
+	}
+	c.acquired = c.mempools[min(c.acquires, len(c.mempools)-1)]
+	c.acquires++
+	c.next = 0
+	return nil
+}
+
+func (c *fakeMempoolClient) GetSizes() (uint32, uint32, uint32, error) {
+	var size int
+	for _, tx := range c.acquired {
+		size += len(tx)
+	}
+	return 1000, uint32(size), uint32(len(c.acquired)), nil
+}
+
+func (c *fakeMempoolClient) NextTx() ([]byte, error) {
+	if c.nextTxErr != nil {
+		return nil, c.nextTxErr
+	}
+	if c.next >= len(c.acquired) {
+		return nil, nil
+	}
+	c.next++
+	return c.acquired[c.next-1], nil
+}
+
+func (c *fakeMempoolClient) Release() error {
+	c.releases++
+	return nil
+}
+
+func TestFetchSnapshotSingleAcquire(t *testing.T) {
+	client := &fakeMempoolClient{
+		mempools: [][][]byte{
+			{[]byte("a"), []byte("bb")},
+			{[]byte("ccc")},
+		},
+	}
+	fetch, err := fetchSnapshot(client)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if client.acquires != 1 || client.releases != 1 {
+		t.Errorf(
+			"got %d acquires and %d releases, want 1 each",
+			client.acquires,
+			client.releases,
+		)
+	}
+	if !fetch.ok() {
+		t.Errorf("unexpected errors: %+v", fetch)
+	}
+	// The sizes describe the same mempool as the drained transactions
+	if fetch.Sizes.NumberOfTxs != 2 || len(fetch.Txs) != 2 ||
+		fetch.Sizes.Size != 3 {
+		t.Errorf(
+			"sizes %+v don't match %d transactions",
+			fetch.Sizes,
+			len(fetch.Txs),
+		)
+	}
+	// The next fetch sees the mempool as of then
+	fetch, err = fetchSnapshot(client)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if fetch.Sizes.NumberOfTxs != 1 || len(fetch.Txs) != 1 {
+		t.Errorf("second fetch got %+v", fetch)
+	}
+}
+
+func TestFetchSnapshotErrors(t *testing.T) {
+	client := &fakeMempoolClient{acquireErr: errors.New("acquire failed")}
+	if _, err := fetchSnapshot(client); err == nil {
+		t.Error("expected an error when acquiring fails")
+	}
+	client = &fakeMempoolClient{
+		mempools:  [][][]byte{{[]byte("a")}},
+		nextTxErr: errors.New("NextTx failed"),
+	}
+	fetch, err := fetchSnapshot(client)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if fetch.TxsErr == nil || fetch.SizesErr != nil || fetch.ok() {
+		t.Errorf("drain error not kept: %+v", fetch)
+	}
+	if client.releases != 0 {
+		t.Error("released after a failed call")
+	}
+}
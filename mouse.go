@@ -0,0 +1,86 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Returns the index of the record shown on line row of content, or -1
+// when that line isn't a row of the transaction list
+func recordAtLine(
+	cfg *Config,
+	records []TxRecord,
+	content Content,
+	row int,
+) int {
+	lines := strings.Split(content.Main, "\n")
+	if row < content.Table.First || row >= content.Table.End ||
+		row >= len(lines) {
+		return -1
+	}
+	for i, record := range records {
+		if strings.Contains(lines[row], shownHash(cfg, record.Hash)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Selects the transaction shown on line row of the list, as the go to
+// prompt does with KEEP_SELECTION. Runs on the tview event loop
+func selectAtLine(cfg *Config, row int) {
+	if !cfg.App.KeepSelection {
+		return
+	}
+	snapshot := getLastSnapshot()
+	records := displayedTransactions(
+		cfg,
+		filterTransactions(snapshot.Records, getFilterState().filters()),
+	)
+	index := recordAtLine(cfg, records, displayed, row)
+	if index < 0 {
+		return
+	}
+	setSelection(selection{Hash: records[index].Hash, Index: index})
+	rerenderFromCache(cfg)
+}
+
+// Selects the clicked transaction in view. Other mouse events, such as the
+// wheel scrolling, are passed on to the view
+func selectOnClick(cfg *Config, view *tview.TextView) func(
+	tview.MouseAction,
+	*tcell.EventMouse,
+) (tview.MouseAction, *tcell.EventMouse) {
+	return func(
+		action tview.MouseAction,
+		event *tcell.EventMouse,
+	) (tview.MouseAction, *tcell.EventMouse) {
+		if action != tview.MouseLeftClick {
+			return action, event
+		}
+		_, y := event.Position()
+		_, top, _, height := view.GetInnerRect()
+		if y < top || y >= top+height {
+			return action, event
+		}
+		offset, _ := view.GetScrollOffset()
+		selectAtLine(cfg, offset+y-top)
+		return action, event
+	}
+}
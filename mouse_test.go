@@ -0,0 +1,118 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func TestRecordAtLine(t *testing.T) {
+	cfg := testConfig()
+	cfg.App.Compact = false
+	records := []TxRecord{
+		{Hash: "aa" + strings.Repeat("0", 62)},
+		{Hash: "bb" + strings.Repeat("0", 62)},
+	}
+	// The largest transaction and recently cleared lines show hashes too
+	content := Content{
+		Main: "Largest: " + records[1].Hash + "\n" +
+			"header\n" +
+			records[0].Hash + "\n" +
+			records[1].Hash + "\n" +
+			"Recently cleared:\n" +
+			records[0].Hash + "\n",
+		Table: lineRange{First: 1, End: 4},
+	}
+	tests := []struct {
+		name string
+		row  int
+		want int
+	}{
+		{"largest", 0, -1},
+		{"header", 1, -1},
+		{"first", 2, 0},
+		{"second", 3, 1},
+		{"cleared", 5, -1},
+		{"below", 10, -1},
+		{"above", -1, -1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := recordAtLine(cfg, records, content, test.row)
+			if got != test.want {
+				t.Errorf("got %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+// Sends a mouse action at row of view, through its mouse capture
+func sendMouse(view *tview.TextView, action tview.MouseAction, row int) {
+	x, y, _, _ := view.GetInnerRect()
+	event := tcell.NewEventMouse(x+1, y+row, tcell.ButtonNone, 0)
+	view.MouseHandler()(action, event, func(tview.Primitive) {})
+}
+
+func TestMouseSelectsAndScrolls(t *testing.T) {
+	defer setLastSnapshot(getLastSnapshot())
+	defer setSelection(getSelection())
+	defer func(saved Content) { displayed = saved }(displayed)
+	snapshot, err := GetDemoSnapshot()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	setLastSnapshot(snapshot)
+	setSelection(selection{})
+	cfg := testConfig()
+	cfg.App.KeepSelection = true
+	displayed = Content{}
+	rerenderFromCache(cfg)
+	records := displayedTransactions(cfg, snapshot.Records)
+	target := records[1]
+	row := lineContaining(
+		displayed.Main,
+		shownHash(cfg, target.Hash),
+		displayed.Table,
+	)
+	if row < 0 {
+		t.Fatal("transaction isn't shown")
+	}
+	// Just tall enough to show the transaction, so there's more to scroll to
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer screen.Fini()
+	screen.SetSize(200, row+1)
+	text.SetRect(0, 0, 200, row+1)
+	text.ScrollToBeginning()
+	text.Draw(screen)
+	text.SetMouseCapture(selectOnClick(cfg, text))
+	defer text.SetMouseCapture(nil)
+
+	sendMouse(text, tview.MouseLeftClick, row)
+	if got := getSelection().Hash; got != target.Hash {
+		t.Errorf("clicking selected %q, want %q", got, target.Hash)
+	}
+
+	sendMouse(text, tview.MouseScrollDown, 0)
+	if offset, _ := text.GetScrollOffset(); offset == 0 {
+		t.Error("scrolling down didn't scroll the transactions")
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	ouroboros "github.com/blinklabs-io/gouroboros"
+)
+
+// Networks tried when detecting the network from the node
+var autoNetworkCandidates = []string{"mainnet", "preprod", "preview"}
+
+// Finds the network the node is on by handshaking with each candidate's
+// magic. Nodes refuse a handshake with the wrong magic, so exactly one
+// candidate should be accepted
+func detectNetwork(
+	candidates []string,
+	handshake func(magic uint32) error,
+) (string, uint32, error) {
+	var accepted []string
+	var magics []uint32
+	for _, name := range candidates {
+		network, ok := ouroboros.NetworkByName(name)
+		if !ok {
+			return "", 0, fmt.Errorf("unknown network: %s", name)
+		}
+		if err := handshake(uint32(network.NetworkMagic)); err != nil {
+			continue
+		}
+		accepted = append(accepted, name)
+		magics = append(magics, uint32(network.NetworkMagic))
+	}
+	switch len(accepted) {
+	case 0:
+		return "", 0, fmt.Errorf(
+			"node accepted none of the known networks: %s",
+			strings.Join(candidates, ", "),
+		)
+	case 1:
+		return accepted[0], magics[0], nil
+	default:
+		return "", 0, fmt.Errorf(
+			"node accepted several networks: %s",
+			strings.Join(accepted, ", "),
+		)
+	}
+}
+
+// Handshake used to detect the network, replaceable for testing
+var networkHandshake = handshakeNetworkMagic
+
+// Attempts a handshake with the configured node using magic
+func handshakeNetworkMagic(magic uint32) error {
+	// Nothing reads this, so leave room for errors from the short-lived
+	// connection
+	errorChan := make(chan error, 10)
+	oConn, err := connectWithMagic(magic, errorChan)
+	if err != nil {
+		return err
+	}
+	return oConn.Close()
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+// Returns a handshake which only succeeds for the given magics
+func acceptMagics(magics ...uint32) func(uint32) error {
+	return func(magic uint32) error {
+		for _, accepted := range magics {
+			if magic == accepted {
+				return nil
+			}
+		}
+		return errors.New("refused")
+	}
+}
+
+func TestDetectNetwork(t *testing.T) {
+	tests := []struct {
+		name      string
+		handshake func(uint32) error
+		want      string
+		wantErr   bool
+	}{
+		{"preprod", acceptMagics(1), "preprod", false},
+		{"mainnet", acceptMagics(764824073), "mainnet", false},
+		{"none", acceptMagics(), "", true},
+		{"several", acceptMagics(1, 2), "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			name, _, err := detectNetwork(autoNetworkCandidates, test.handshake)
+			if name != test.want || (err != nil) != test.wantErr {
+				t.Errorf("got (%q, %v)", name, err)
+			}
+		})
+	}
+}
+
+func TestPopulateNetworkMagicPrecedence(t *testing.T) {
+	defer func(saved func(uint32) error) { networkHandshake = saved }(
+		networkHandshake,
+	)
+	networkHandshake = acceptMagics(2)
+	tests := []struct {
+		name        string
+		appNetwork  string
+		nodeNetwork string
+		auto        bool
+		want        string
+	}{
+		{"default network", "", "mainnet", false, "mainnet"},
+		{"auto beats default", "", "mainnet", true, "preview"},
+		{"network beats auto", "preprod", "mainnet", true, "preprod"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.App.Network = test.appNetwork
+			cfg.App.AutoNetwork = test.auto
+			cfg.Node.Network = test.nodeNetwork
+			if err := cfg.populateNetworkMagic(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if cfg.Node.Network != test.want {
+				t.Errorf("got %q, want %q", cfg.Node.Network, test.want)
+			}
+		})
+	}
+}
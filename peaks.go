@@ -0,0 +1,82 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Largest mempool seen since launch, and when
+type MempoolPeaks struct {
+	Size      uint32
+	SizeTime  time.Time
+	Count     int
+	CountTime time.Time
+}
+
+// Records a mempool size in bytes, returning whether it's a new peak
+func (p *MempoolPeaks) ObserveSize(size uint32, now time.Time) bool {
+	if size <= p.Size && !p.SizeTime.IsZero() {
+		return false
+	}
+	p.Size = size
+	p.SizeTime = now
+	return true
+}
+
+// Records a transaction count, returning whether it's a new peak
+func (p *MempoolPeaks) ObserveCount(count int, now time.Time) bool {
+	if count <= p.Count && !p.CountTime.IsZero() {
+		return false
+	}
+	p.Count = count
+	p.CountTime = now
+	return true
+}
+
+// Records the sizes and transaction count of a snapshot, skipping any which
+// couldn't be read in full
+func (p *MempoolPeaks) Observe(snapshot Snapshot, now time.Time) {
+	if snapshot.SizesErr == nil {
+		p.ObserveSize(snapshot.Sizes.Size, now)
+	}
+	if snapshot.SizesPolled || snapshot.TxErr == nil {
+		p.ObserveCount(snapshot.transactionCount(), now)
+	}
+}
+
+// Formats the peaks on one line, or nothing before anything was observed
+func FormatPeaks(peaks MempoolPeaks, now time.Time, relative bool) string {
+	if peaks.SizeTime.IsZero() && peaks.CountTime.IsZero() {
+		return ""
+	}
+	var line string
+	if !peaks.SizeTime.IsZero() {
+		line += fmt.Sprintf(
+			" [blue]%d[white] bytes (%s)",
+			peaks.Size,
+			formatTimestamp(peaks.SizeTime, now, relative),
+		)
+	}
+	if !peaks.CountTime.IsZero() {
+		line += fmt.Sprintf(
+			" [blue]%d[white] transactions (%s)",
+			peaks.Count,
+			formatTimestamp(peaks.CountTime, now, relative),
+		)
+	}
+	return " [white]Peak since launch:" + line + "\n"
+}
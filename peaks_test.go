@@ -0,0 +1,104 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMempoolPeaksObserve(t *testing.T) {
+	start := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	var peaks MempoolPeaks
+	steps := []struct {
+		size      uint32
+		count     int
+		wantSize  uint32
+		wantCount int
+		wantTime  time.Time
+	}{
+		{100, 2, 100, 2, start},
+		{300, 5, 300, 5, start.Add(time.Minute)},
+		// Smaller and equal values keep the earlier peak and its time
+		{200, 5, 300, 5, start.Add(time.Minute)},
+		{300, 1, 300, 5, start.Add(time.Minute)},
+	}
+	for i, step := range steps {
+		now := start.Add(time.Duration(i) * time.Minute)
+		peaks.Observe(
+			Snapshot{
+				Sizes:   MempoolSizes{Size: step.size},
+				Drained: step.count,
+			},
+			now,
+		)
+		if peaks.Size != step.wantSize || peaks.Count != step.wantCount {
+			t.Errorf(
+				"step %d: got %d bytes, %d txs, want %d, %d",
+				i,
+				peaks.Size,
+				peaks.Count,
+				step.wantSize,
+				step.wantCount,
+			)
+		}
+		if !peaks.SizeTime.Equal(step.wantTime) ||
+			!peaks.CountTime.Equal(step.wantTime) {
+			t.Errorf(
+				"step %d: got times %s, %s, want %s",
+				i,
+				peaks.SizeTime,
+				peaks.CountTime,
+				step.wantTime,
+			)
+		}
+	}
+}
+
+func TestMempoolPeaksSkipsErrors(t *testing.T) {
+	var peaks MempoolPeaks
+	peaks.Observe(
+		Snapshot{
+			SizesErr: errors.New("GetSizes failed"),
+			TxErr:    errors.New("NextTx failed"),
+			Drained:  4,
+		},
+		time.Now(),
+	)
+	if !peaks.SizeTime.IsZero() || !peaks.CountTime.IsZero() {
+		t.Errorf("recorded a peak from failed queries: %+v", peaks)
+	}
+	if got := FormatPeaks(peaks, time.Now(), true); got != "" {
+		t.Errorf("expected nothing without peaks, got %q", got)
+	}
+}
+
+func TestFormatPeaks(t *testing.T) {
+	now := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	peaks := MempoolPeaks{
+		Size:      1234,
+		SizeTime:  now.Add(-time.Minute),
+		Count:     7,
+		CountTime: now,
+	}
+	got := FormatPeaks(peaks, now, false)
+	for _, want := range []string{"1234", "11:59:00", "7", "12:00:00"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("%q not in %q", want, got)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// Polls the mempool sizes every SIZES_REFRESH between full
+// refreshes. GetSizes is cheap, while draining every transaction isn't, so
+// this keeps the sizes line current without draining more often
+func startSizesLoop(cfg *Config, errorChan chan error) {
+	if cfg.App.SizesRefresh == 0 || cfg.App.Demo || cfg.App.FakeData {
+		return
+	}
+	ticker := time.NewTicker(cfg.App.SizesRefresh.Duration())
+	go runTicker(ticker.C, nil, func() {
+		if paused.Load() {
+			return
+		}
+		_, err := safeFetch(
+			func() Content {
+				refreshSizes(cfg, errorChan)
+				return Content{}
+			},
+			cfg.App.RecoverPanics,
+		)
+		if err != nil {
+			log.Printf("failed to poll mempool sizes: %s", err)
+		}
+	})
+}
+
+// Calls poll for every tick until done is closed
+func runTicker(ticks <-chan time.Time, done <-chan struct{}, poll func()) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticks:
+			poll()
+		}
+	}
+}
+
+// Reads the sizes from the node into the last snapshot and redraws it
+func refreshSizes(cfg *Config, errorChan chan error) {
+	if getLastSnapshot().Time.IsZero() {
+		// Nothing to update until the first full refresh
+		return
+	}
+	sizes, err := GetCurrentSizes(errorChan)
+	if err != nil {
+		log.Printf("failed to poll mempool sizes: %s", err)
+		return
+	}
+	snapshot := updateLastSnapshotSizes(sizes)
+	current := renderContent(cfg, snapshot)
+	redraw.Request("content", func() {
+		updateUI(current)
+	})
+}
+
+// Reads just the mempool sizes over the shared node connection
+func GetCurrentSizes(errorChan chan error) (MempoolSizes, error) {
+	mempoolMutex.Lock()
+	defer mempoolMutex.Unlock()
+	oConn, err := nodeConn.Get(errorChan)
+	if err != nil {
+		return MempoolSizes{}, fmt.Errorf("failed to connect to node: %w", err)
+	}
+	sizes, err := GetSizes(oConn.LocalTxMonitor().Client)
+	if err != nil {
+		nodeConn.Close()
+		return MempoolSizes{}, err
+	}
+	if err := oConn.LocalTxMonitor().Client.Release(); err != nil {
+		nodeConn.Close()
+		return MempoolSizes{}, fmt.Errorf(
+			"failed to release mempool snapshot: %w",
+			err,
+		)
+	}
+	return sizes, nil
+}
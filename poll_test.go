@@ -0,0 +1,41 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRunTicker(t *testing.T) {
+	ticks := make(chan time.Time)
+	done := make(chan struct{})
+	polled := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		runTicker(ticks, done, func() { polled <- struct{}{} })
+		close(stopped)
+	}()
+	for range 3 {
+		ticks <- time.Now()
+		<-polled
+	}
+	close(done)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("runTicker didn't stop after done was closed")
+	}
+}
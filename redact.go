@@ -0,0 +1,52 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Random per-run key so redacted hashes can't be matched against the
+// public mempool by hashing candidate transaction hashes
+var redactKey = newRedactKey()
+
+func newRedactKey() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// Replaces a transaction hash with a keyed digest of the same length. The
+// same hash always maps to the same value for a given key
+func redactHash(hash string, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(hash))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Returns a copy of records with their hashes redacted
+func redactRecords(records []TxRecord, key []byte) []TxRecord {
+	redacted := make([]TxRecord, len(records))
+	for i, record := range records {
+		record.Hash = redactHash(record.Hash, key)
+		redacted[i] = record
+	}
+	return redacted
+}
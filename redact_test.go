@@ -0,0 +1,47 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "testing"
+
+func TestRedactHash(t *testing.T) {
+	key := []byte("key")
+	hash := "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	redacted := redactHash(hash, key)
+	if redacted == hash || len(redacted) != len(hash) {
+		t.Errorf("got %q", redacted)
+	}
+	if redactHash(hash, key) != redacted {
+		t.Error("redaction isn't stable for a key")
+	}
+	if redactHash(hash, []byte("other")) == redacted {
+		t.Error("redaction doesn't depend on the key")
+	}
+}
+
+func TestRedactRecords(t *testing.T) {
+	key := []byte("key")
+	records := []TxRecord{{Hash: "a", Size: 1}, {Hash: "b", Size: 2}}
+	redacted := redactRecords(records, key)
+	if records[0].Hash != "a" {
+		t.Error("original records modified")
+	}
+	for i, record := range redacted {
+		if record.Hash != redactHash(records[i].Hash, key) ||
+			record.Size != records[i].Size {
+			t.Errorf("record %d: got %+v", i, record)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strconv"
+
+	"github.com/blinklabs-io/gouroboros/ledger"
+)
+
+// Returns the number of redeemers in a transaction's witness set, a proxy
+// for how many scripts it runs. Transactions from before Alonzo have none
+func redeemerCount(tx ledger.Transaction) int {
+	switch t := tx.(type) {
+	case *ledger.AlonzoTransaction:
+		return len(t.WitnessSet.Redeemers)
+	case *ledger.BabbageTransaction:
+		return len(t.WitnessSet.Redeemers)
+	case *ledger.ConwayTransaction:
+		return len(t.WitnessSet.Redeemers.Redeemers)
+	}
+	return 0
+}
+
+var redeemersColumn = column{
+	header:   "Redeemers:",
+	width:    11,
+	priority: 3,
+	value: func(record TxRecord) string {
+		return strconv.Itoa(record.Redeemers)
+	},
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/blinklabs-io/gouroboros/ledger"
+	"github.com/blinklabs-io/gouroboros/ledger/alonzo"
+	"github.com/blinklabs-io/gouroboros/ledger/conway"
+)
+
+func TestRedeemerCount(t *testing.T) {
+	var alonzoTx ledger.AlonzoTransaction
+	var babbageTx ledger.BabbageTransaction
+	babbageTx.WitnessSet.Redeemers = []alonzo.AlonzoRedeemer{
+		{Tag: 0, Index: 0},
+		{Tag: 1, Index: 0},
+	}
+	redeemers := make(
+		map[conway.ConwayRedeemerKey]conway.ConwayRedeemerValue,
+	)
+	for _, key := range []conway.ConwayRedeemerKey{
+		{Tag: 0, Index: 0},
+		{Tag: 0, Index: 1},
+		{Tag: 1, Index: 0},
+	} {
+		redeemers[key] = conway.ConwayRedeemerValue{}
+	}
+	var conwayTx ledger.ConwayTransaction
+	conwayTx.WitnessSet.Redeemers.Redeemers = redeemers
+	tests := []struct {
+		name string
+		tx   ledger.Transaction
+		want int
+	}{
+		{"none", &alonzoTx, 0},
+		{"babbage", &babbageTx, 2},
+		{"conway", &conwayTx, 3},
+		{"before alonzo", fakeTx{}, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := redeemerCount(test.tx); got != test.want {
+				t.Errorf("got %d redeemers, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRedeemerCountUndecodable(t *testing.T) {
+	record, err := ClassifyTransaction([]byte{0xff, 0x00}, nil)
+	if err == nil {
+		t.Fatal("expected an error for undecodable bytes")
+	}
+	if record.Redeemers != 0 {
+		t.Errorf("got %d redeemers, want 0", record.Redeemers)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Refresh interval, configured as a duration such as 500ms or 2s, or as a
+// bare number of seconds
+type RefreshInterval time.Duration
+
+// Parses value for envconfig
+func (r *RefreshInterval) Decode(value string) error {
+	value = strings.TrimSpace(value)
+	if seconds, err := strconv.ParseUint(value, 10, 32); err == nil {
+		*r = RefreshInterval(time.Duration(seconds) * time.Second)
+		return nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf(
+			"invalid interval: %q (expected seconds or a duration such as 500ms)",
+			value,
+		)
+	}
+	*r = RefreshInterval(d)
+	return nil
+}
+
+func (r RefreshInterval) Duration() time.Duration {
+	return time.Duration(r)
+}
+
+func (r RefreshInterval) String() string {
+	return r.Duration().String()
+}
+
+// Longest interval the refresh keys slow refreshing down to
+const maxRefreshInterval = time.Minute
+
+// Intervals the refresh keys step through
+var refreshSteps = []time.Duration{
+	minRefreshInterval,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2 * time.Second,
+	3 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	15 * time.Second,
+	30 * time.Second,
+	maxRefreshInterval,
+}
+
+var refreshMutex sync.Mutex
+var currentRefresh = 3 * time.Second
+
+// Returns the interval refreshes currently run at
+func getRefreshInterval() time.Duration {
+	refreshMutex.Lock()
+	defer refreshMutex.Unlock()
+	return currentRefresh
+}
+
+// Sets the interval refreshes run at, raised to minRefreshInterval if lower
+func setRefreshInterval(d time.Duration) {
+	refreshMutex.Lock()
+	defer refreshMutex.Unlock()
+	currentRefresh = max(d, minRefreshInterval)
+}
+
+// Moves the refresh interval to the next longer step, or the next shorter
+// one when step is negative, and returns it
+func stepRefreshInterval(step int) time.Duration {
+	refreshMutex.Lock()
+	defer refreshMutex.Unlock()
+	currentRefresh = nextRefreshStep(currentRefresh, step)
+	return currentRefresh
+}
+
+// Returns the first step beyond current in the direction of step, staying
+// put at either end. Current needn't be a step itself, as REFRESH may be
+// anything, and is kept when it's already longer than the longest step
+func nextRefreshStep(current time.Duration, step int) time.Duration {
+	if step < 0 {
+		for i := len(refreshSteps) - 1; i >= 0; i-- {
+			if refreshSteps[i] < current {
+				return refreshSteps[i]
+			}
+		}
+		return refreshSteps[0]
+	}
+	for _, d := range refreshSteps {
+		if d > current {
+			return d
+		}
+	}
+	return current
+}
@@ -0,0 +1,162 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+func TestRefreshIntervalDecode(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"3", 3 * time.Second, false},
+		{" 10 ", 10 * time.Second, false},
+		{"0", 0, false},
+		{"500ms", 500 * time.Millisecond, false},
+		{"2s", 2 * time.Second, false},
+		{"1m30s", 90 * time.Second, false},
+		{"1.5", 0, true},
+		{"-1", 0, true},
+		{"soon", 0, true},
+		{"", 0, true},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			var r RefreshInterval
+			err := r.Decode(test.value)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %t", err, test.wantErr)
+			}
+			if !test.wantErr && r.Duration() != test.want {
+				t.Errorf("got %s, want %s", r, test.want)
+			}
+		})
+	}
+}
+
+func TestRefreshIntervalFromEnv(t *testing.T) {
+	t.Setenv("REFRESH", "250ms")
+	var app AppConfig
+	if err := envconfig.Process("", &app); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := app.Refresh.Duration(); got != 250*time.Millisecond {
+		t.Errorf("got %s, want 250ms", got)
+	}
+}
+
+func TestSecondaryRefreshIntervalsFromEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		sizes    string
+		idleMax  string
+		wantSize time.Duration
+		wantIdle time.Duration
+	}{
+		{"seconds", "1", "30", time.Second, 30 * time.Second},
+		{"durations", "500ms", "1m", 500 * time.Millisecond, time.Minute},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("SIZES_REFRESH", test.sizes)
+			t.Setenv("IDLE_MAX_REFRESH", test.idleMax)
+			var app AppConfig
+			if err := envconfig.Process("", &app); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := app.SizesRefresh.Duration(); got != test.wantSize {
+				t.Errorf("got sizes refresh %s, want %s", got, test.wantSize)
+			}
+			if got := app.IdleMaxRefresh.Duration(); got != test.wantIdle {
+				t.Errorf("got idle max refresh %s, want %s", got, test.wantIdle)
+			}
+		})
+	}
+}
+
+func TestConfigValidateRefreshMinimum(t *testing.T) {
+	tests := []struct {
+		refresh time.Duration
+		want    time.Duration
+	}{
+		{0, minRefreshInterval},
+		{10 * time.Millisecond, minRefreshInterval},
+		{500 * time.Millisecond, 500 * time.Millisecond},
+		{5 * time.Second, 5 * time.Second},
+	}
+	for _, test := range tests {
+		cfg := testConfig()
+		cfg.App.Refresh = RefreshInterval(test.refresh)
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got := cfg.App.Refresh.Duration(); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.refresh, got, test.want)
+		}
+	}
+}
+
+func TestNextRefreshStep(t *testing.T) {
+	tests := []struct {
+		current time.Duration
+		step    int
+		want    time.Duration
+	}{
+		{3 * time.Second, 1, 5 * time.Second},
+		{3 * time.Second, -1, 2 * time.Second},
+		{4 * time.Second, 1, 5 * time.Second},
+		{4 * time.Second, -1, 3 * time.Second},
+		{minRefreshInterval, -1, minRefreshInterval},
+		{maxRefreshInterval, 1, maxRefreshInterval},
+		{5 * time.Minute, 1, 5 * time.Minute},
+		{5 * time.Minute, -1, maxRefreshInterval},
+	}
+	for _, test := range tests {
+		got := nextRefreshStep(test.current, test.step)
+		if got != test.want {
+			t.Errorf(
+				"%s by %d: got %s, want %s",
+				test.current,
+				test.step,
+				got,
+				test.want,
+			)
+		}
+	}
+}
+
+func TestStepRefreshInterval(t *testing.T) {
+	defer setRefreshInterval(getRefreshInterval())
+	setRefreshInterval(0)
+	if got := getRefreshInterval(); got != minRefreshInterval {
+		t.Errorf("got %s, want %s", got, minRefreshInterval)
+	}
+	setRefreshInterval(time.Second)
+	if got := stepRefreshInterval(1); got != 2*time.Second {
+		t.Errorf("got %s, want 2s", got)
+	}
+	if got := getRefreshInterval(); got != 2*time.Second {
+		t.Errorf("got %s after stepping, want 2s", got)
+	}
+	if got := stepRefreshInterval(-1); got != time.Second {
+		t.Errorf("got %s, want 1s", got)
+	}
+}
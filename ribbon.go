@@ -0,0 +1,84 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// Default ribbon width when the terminal size is unknown
+const defaultRibbonWidth = 80
+
+// Counts the transactions for each icon, ignoring unlabeled transactions
+func iconCounts(records []TxRecord) map[string]int {
+	counts := make(map[string]int)
+	for _, record := range records {
+		if record.Icon != "" {
+			counts[record.Icon]++
+		}
+	}
+	return counts
+}
+
+// Builds a line of icons whose proportions match counts, filling width
+// cells. Icons are two cells wide
+func iconRibbon(counts map[string]int, width int) string {
+	slots := width / 2
+	var total int
+	icons := make([]string, 0, len(counts))
+	for icon, count := range counts {
+		if count <= 0 {
+			continue
+		}
+		total += count
+		icons = append(icons, icon)
+	}
+	if total == 0 || slots <= 0 {
+		return ""
+	}
+	// Most common first, with ties broken by icon for a stable ribbon
+	sort.Slice(icons, func(i, j int) bool {
+		if counts[icons[i]] != counts[icons[j]] {
+			return counts[icons[i]] > counts[icons[j]]
+		}
+		return icons[i] < icons[j]
+	})
+	// Largest remainder apportionment of slots across icons
+	shares := make([]int, len(icons))
+	remainders := make([]int, len(icons))
+	var used int
+	for i, icon := range icons {
+		shares[i] = counts[icon] * slots / total
+		remainders[i] = counts[icon] * slots % total
+		used += shares[i]
+	}
+	order := make([]int, len(icons))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]] > remainders[order[b]]
+	})
+	for i := 0; used < slots; i++ {
+		shares[order[i%len(order)]]++
+		used++
+	}
+	var sb strings.Builder
+	for i, icon := range icons {
+		sb.WriteString(strings.Repeat(icon, shares[i]))
+	}
+	return sb.String()
+}
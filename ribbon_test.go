@@ -0,0 +1,56 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "testing"
+
+func TestIconRibbon(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts map[string]int
+		width  int
+		want   string
+	}{
+		{"empty", map[string]int{}, 20, ""},
+		{"too narrow", map[string]int{"🐱": 1}, 1, ""},
+		{"single", map[string]int{"🐱": 3}, 6, "🐱🐱🐱"},
+		{
+			"proportional",
+			map[string]int{"🐱": 3, "🍨": 1},
+			8,
+			"🐱🐱🐱🍨",
+		},
+		{
+			"largest remainder",
+			map[string]int{"🐱": 2, "🍨": 1},
+			4,
+			"🐱🍨",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := iconRibbon(test.counts, test.width); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestIconCounts(t *testing.T) {
+	counts := iconCounts([]TxRecord{{Icon: "🐱"}, {Icon: "🐱"}, {}})
+	if len(counts) != 1 || counts["🐱"] != 2 {
+		t.Errorf("got %v", counts)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// Limits how many transactions are classified when the mempool is huge,
+// keeping the largest ones and a random selection of the rest
+type Sampler struct {
+	sync.Mutex
+	size int
+	rng  *rand.Rand
+}
+
+var txSampler = NewSampler(0, rand.New(rand.NewSource(rand.Int63())))
+
+// Creates a sampler keeping at most size transactions. A zero size keeps
+// every transaction
+func NewSampler(size int, rng *rand.Rand) *Sampler {
+	return &Sampler{size: size, rng: rng}
+}
+
+// Returns at most the sampler's size of txs, and whether any were left
+// out. Half of the sample is the largest transactions, so they're never
+// missed, and the rest is picked at random. Order is preserved
+func (s *Sampler) Sample(txs [][]byte) ([][]byte, bool) {
+	if s.size <= 0 || len(txs) <= s.size {
+		return txs, false
+	}
+	s.Lock()
+	defer s.Unlock()
+	order := make([]int, len(txs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return len(txs[order[a]]) > len(txs[order[b]])
+	})
+	largest := max(s.size/2, 1)
+	rest := order[largest:]
+	s.rng.Shuffle(len(rest), func(a, b int) {
+		rest[a], rest[b] = rest[b], rest[a]
+	})
+	keep := make([]bool, len(txs))
+	for _, i := range order[:s.size] {
+		keep[i] = true
+	}
+	sampled := make([][]byte, 0, s.size)
+	for i, tx := range txs {
+		if keep[i] {
+			sampled = append(sampled, tx)
+		}
+	}
+	return sampled, true
+}
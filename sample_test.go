@@ -0,0 +1,72 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSamplerSample(t *testing.T) {
+	// Transactions identified by their length
+	txs := make([][]byte, 10)
+	for i := range txs {
+		txs[i] = make([]byte, i+1)
+	}
+	tests := []struct {
+		name        string
+		size        int
+		wantLen     int
+		wantSampled bool
+	}{
+		{"disabled", 0, 10, false},
+		{"larger than mempool", 20, 10, false},
+		{"equal to mempool", 10, 10, false},
+		{"sampled", 4, 4, true},
+		{"single", 1, 1, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewSampler(test.size, rand.New(rand.NewSource(1)))
+			sampled, ok := s.Sample(txs)
+			if len(sampled) != test.wantLen || ok != test.wantSampled {
+				t.Fatalf(
+					"got (%d, %t), want (%d, %t)",
+					len(sampled),
+					ok,
+					test.wantLen,
+					test.wantSampled,
+				)
+			}
+			// Order is preserved
+			for i := 1; i < len(sampled); i++ {
+				if len(sampled[i]) < len(sampled[i-1]) {
+					t.Errorf("order not preserved at %d", i)
+				}
+			}
+			// The largest half is always kept
+			for i := 0; i < max(test.size/2, 1) && ok; i++ {
+				want := len(txs) - i
+				found := false
+				for _, tx := range sampled {
+					found = found || len(tx) == want
+				}
+				if !found {
+					t.Errorf("largest tx of size %d missing", want)
+				}
+			}
+		})
+	}
+}
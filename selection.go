@@ -0,0 +1,65 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sync"
+)
+
+// The transaction picked with the go to prompt, by its hash as shown and
+// its position in the list. It stays highlighted and in view across
+// refreshes
+type selection struct {
+	Hash  string
+	Index int
+}
+
+var selectionMutex sync.Mutex
+var currentSelection selection
+
+func getSelection() selection {
+	selectionMutex.Lock()
+	defer selectionMutex.Unlock()
+	return currentSelection
+}
+
+func setSelection(sel selection) {
+	selectionMutex.Lock()
+	defer selectionMutex.Unlock()
+	currentSelection = sel
+}
+
+// Finds the selected transaction in records, as they're shown, after a
+// refresh which may have moved it. When it's gone, the transaction now in
+// its place is selected instead, or the last one if the list got shorter.
+// Nothing is selected once the list is empty
+func reselect(records []TxRecord, sel selection) selection {
+	if sel.Hash == "" || len(records) == 0 {
+		return selection{}
+	}
+	for i, record := range records {
+		if record.Hash == sel.Hash {
+			return selection{Hash: record.Hash, Index: i}
+		}
+	}
+	index := min(max(sel.Index, 0), len(records)-1)
+	return selection{Hash: records[index].Hash, Index: index}
+}
+
+// Reports whether record is the selected transaction
+func isSelected(record TxRecord) bool {
+	hash := getSelection().Hash
+	return hash != "" && record.Hash == hash
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+)
+
+func TestReselect(t *testing.T) {
+	records := []TxRecord{{Hash: "a"}, {Hash: "b"}, {Hash: "c"}}
+	tests := []struct {
+		name    string
+		records []TxRecord
+		sel     selection
+		want    selection
+	}{
+		{"nothing selected", records, selection{}, selection{}},
+		{"same place", records, selection{"b", 1}, selection{"b", 1}},
+		{"moved", records, selection{"c", 0}, selection{"c", 2}},
+		{"gone", records, selection{"x", 1}, selection{"b", 1}},
+		{"gone past the end", records, selection{"x", 7}, selection{"c", 2}},
+		{"list emptied", nil, selection{"b", 1}, selection{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := reselect(test.records, test.sel); got != test.want {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestIsSelected(t *testing.T) {
+	defer setSelection(getSelection())
+	setSelection(selection{})
+	if isSelected(TxRecord{}) {
+		t.Error("unhashed record selected without a selection")
+	}
+	setSelection(selection{Hash: "b", Index: 1})
+	if !isSelected(TxRecord{Hash: "b"}) || isSelected(TxRecord{Hash: "a"}) {
+		t.Error("wrong record selected")
+	}
+}
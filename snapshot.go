@@ -0,0 +1,249 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/blinklabs-io/gouroboros/cbor"
+	lcommon "github.com/blinklabs-io/gouroboros/ledger/common"
+)
+
+// Everything read from the node in a single refresh
+type Snapshot struct {
+	Time time.Time
+	// Warning shown above the sizes, such as the node still syncing
+	Warning  string
+	Sizes    MempoolSizes
+	SizesErr error
+	// Number of distinct transactions read from the mempool
+	Drained int
+	// Classified transactions, in the order the node returned them
+	Records []TxRecord
+	// Error from draining or classifying transactions
+	TxErr error
+	// Content hash of the transactions, see snapshotHash
+	Hash string
+	// Transactions which recently left the mempool
+	Cleared []clearedTx
+	// Previous transactions still shown after the mempool emptied
+	Lingering []TxRecord
+	// Whether Records only holds a sample of the drained transactions
+	Sampled bool
+	// Number of records spending an input another record also spends
+	Conflicts int
+	// Whether Sizes was polled after the transactions were drained, in
+	// which case it has the more current transaction count
+	SizesPolled bool
+}
+
+// The text shown in each pane for a refresh
+type Content struct {
+	Main string
+	// The lines of Main holding the transaction list
+	Table lineRange
+	Watch string
+	// The selected transaction's hash as shown in Main, if any
+	Selected string
+}
+
+// Classifies raw transactions from the node and fills in their ages
+func NewSnapshot(
+	sizes MempoolSizes,
+	sizesErr error,
+	txs [][]byte,
+	txsErr error,
+) Snapshot {
+	snapshot := Snapshot{
+		Time:     time.Now(),
+		Sizes:    sizes,
+		SizesErr: sizesErr,
+		TxErr:    txsErr,
+	}
+	hashes, hashErr := rawTxHashes(txs)
+	if hashErr != nil {
+		log.Printf("failed to hash drained transactions: %s", hashErr)
+	}
+	txs, hashes = dedupeTxs(txs, hashes)
+	snapshot.Drained = len(txs)
+	if sizesErr == nil && txsErr == nil &&
+		countMismatch(sizes.NumberOfTxs, len(txs)) {
+		log.Printf(
+			"transaction count mismatch: GetSizes reported %d, drained %d",
+			sizes.NumberOfTxs,
+			len(txs),
+		)
+	}
+	// Ages come from every drained transaction, not just those sampled or
+	// classified, so skipped transactions keep their first-seen times
+	txAges.Observe(hashes, snapshot.Time, txsErr == nil && hashErr == nil)
+	txs, snapshot.Sampled = txSampler.Sample(txs)
+	// Built once per snapshot, rather than for each transaction
+	chain := configuredClassifiers()
+	records := make([]TxRecord, 0, len(txs))
+	for _, txRawBytes := range txs {
+		record, err := ClassifyTransaction(txRawBytes, chain)
+		if err != nil {
+			if snapshot.TxErr == nil {
+				snapshot.TxErr = err
+			}
+			break
+		}
+		records = append(records, record)
+	}
+	snapshot.Records = txAges.Fill(records, snapshot.Time)
+	snapshot.Conflicts = markConflicts(snapshot.Records)
+	snapshot.Hash = snapshotHash(snapshot.Records)
+	if snapshot.TxErr == nil && !snapshot.Sampled {
+		// A partial drain or a sample would make the missing transactions
+		// look cleared
+		snapshot.Cleared = recentlyCleared.Update(
+			snapshot.Records,
+			snapshot.Time,
+		)
+		snapshot.Lingering = txLinger.Update(snapshot.Records)
+	}
+	return snapshot
+}
+
+// Returns the hash of each raw transaction, stopping at the first which
+// can't be decoded. This only decodes the outer array, which is much
+// cheaper than classifying
+func rawTxHashes(txs [][]byte) ([]string, error) {
+	hashes := make([]string, 0, len(txs))
+	for _, txRawBytes := range txs {
+		var parts []cbor.RawMessage
+		if _, err := cbor.Decode(txRawBytes, &parts); err != nil {
+			return hashes, err
+		}
+		if len(parts) == 0 {
+			return hashes, errors.New("empty transaction")
+		}
+		// The transaction ID is the hash of the body, the first element
+		hashes = append(
+			hashes,
+			lcommon.Blake2b256Hash(parts[0]).String(),
+		)
+	}
+	return hashes, nil
+}
+
+// Reports whether both the sizes and the transactions were read. Connecting
+// can work while every query on the connection fails
+func (s Snapshot) Healthy() bool {
+	return s.SizesErr == nil && s.TxErr == nil
+}
+
+// Drops repeated transactions, keeping the first occurrence, along with
+// their hashes. The node can return a transaction twice in one drain while
+// the mempool churns. Transactions past the end of hashes, which couldn't
+// be hashed, are kept as is
+func dedupeTxs(txs [][]byte, hashes []string) ([][]byte, []string) {
+	seen := make(map[string]bool, len(hashes))
+	dedupedTxs := make([][]byte, 0, len(txs))
+	dedupedHashes := make([]string, 0, len(hashes))
+	for i, tx := range txs {
+		if i < len(hashes) {
+			if seen[hashes[i]] {
+				continue
+			}
+			seen[hashes[i]] = true
+			dedupedHashes = append(dedupedHashes, hashes[i])
+		}
+		dedupedTxs = append(dedupedTxs, tx)
+	}
+	if duplicates := len(txs) - len(dedupedTxs); duplicates > 0 {
+		log.Printf("dropped %d duplicate transactions from drain", duplicates)
+	}
+	return dedupedTxs, dedupedHashes
+}
+
+// Returns a hash of the transaction hashes and sizes which only changes when
+// the mempool contents do, regardless of the order the node returned them in
+func snapshotHash(txs []TxRecord) string {
+	entries := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		entries = append(entries, fmt.Sprintf("%s:%d", tx.Hash, tx.Size))
+	}
+	sort.Strings(entries)
+	h := sha256.New()
+	for _, entry := range entries {
+		h.Write([]byte(entry))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+var snapshotMutex sync.Mutex
+var lastSnapshot Snapshot
+var mempoolPeaks MempoolPeaks
+
+// Set once the first snapshot has been read, for readiness checks
+var snapshotReady atomic.Bool
+
+// Whether the most recent attempt to read a snapshot worked, including
+// its queries, for health checks
+var snapshotHealthy atomic.Bool
+
+// Returns the most recent snapshot read from the node
+func getLastSnapshot() Snapshot {
+	snapshotMutex.Lock()
+	defer snapshotMutex.Unlock()
+	return lastSnapshot
+}
+
+func setLastSnapshot(snapshot Snapshot) {
+	snapshotMutex.Lock()
+	defer snapshotMutex.Unlock()
+	lastSnapshot = snapshot
+	mempoolPeaks.Observe(snapshot, snapshot.Time)
+	snapshotReady.Store(true)
+}
+
+// Returns the largest mempool seen since launch
+func getMempoolPeaks() MempoolPeaks {
+	snapshotMutex.Lock()
+	defer snapshotMutex.Unlock()
+	return mempoolPeaks
+}
+
+// Replaces the sizes in the last snapshot with newer ones, returning the
+// updated snapshot
+func updateLastSnapshotSizes(sizes MempoolSizes) Snapshot {
+	snapshotMutex.Lock()
+	defer snapshotMutex.Unlock()
+	lastSnapshot.Sizes = sizes
+	lastSnapshot.SizesErr = nil
+	lastSnapshot.SizesPolled = true
+	mempoolPeaks.Observe(lastSnapshot, time.Now())
+	return lastSnapshot
+}
+
+// Returns the number of transactions in the mempool, from the sizes if
+// they're newer than the drain
+func (s Snapshot) transactionCount() int {
+	if s.SizesPolled {
+		return int(s.Sizes.NumberOfTxs)
+	}
+	return s.Drained
+}
@@ -0,0 +1,168 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// Swaps in a fresh age tracker and sampler for the duration of a test
+func withSnapshotState(t *testing.T, sampleSize int) {
+	t.Helper()
+	ages, sampler := txAges, txSampler
+	txAges = NewAgeTracker()
+	txSampler = NewSampler(sampleSize, rand.New(rand.NewSource(1)))
+	t.Cleanup(func() {
+		txAges, txSampler = ages, sampler
+	})
+}
+
+func loadDemoTxs(t *testing.T) [][]byte {
+	t.Helper()
+	_, txs, err := LoadDemoSnapshot()
+	if err != nil {
+		t.Fatalf("loading demo snapshot: %s", err)
+	}
+	if len(txs) < 4 {
+		t.Fatalf("demo snapshot only has %d transactions", len(txs))
+	}
+	return txs
+}
+
+func TestRawTxHashesMatchClassified(t *testing.T) {
+	txs := loadDemoTxs(t)
+	hashes, err := rawTxHashes(txs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for i, txRawBytes := range txs {
+		record, err := ClassifyTransaction(txRawBytes, nil)
+		if err != nil {
+			t.Fatalf("classifying tx %d: %s", i, err)
+		}
+		if hashes[i] != record.Hash {
+			t.Errorf("tx %d: got %s, want %s", i, hashes[i], record.Hash)
+		}
+	}
+	if _, err := rawTxHashes([][]byte{{0xff}}); err == nil {
+		t.Error("expected an error for invalid CBOR")
+	}
+}
+
+func TestSampledSnapshotKeepsAges(t *testing.T) {
+	withSnapshotState(t, 2)
+	txs := loadDemoTxs(t)
+	hashes, err := rawTxHashes(txs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := NewSnapshot(MempoolSizes{}, nil, txs, nil)
+	if !first.Sampled {
+		t.Fatal("expected a sampled snapshot")
+	}
+	// Every drained transaction is aged, sampled or not
+	for _, hash := range hashes {
+		if !txAges.firstSeen[hash].Equal(first.Time) {
+			t.Errorf("%s: not aged from the first snapshot", hash)
+		}
+	}
+	second := NewSnapshot(MempoolSizes{}, nil, txs, nil)
+	for _, record := range second.Records {
+		if !record.FirstSeen.Equal(first.Time) {
+			t.Errorf(
+				"%s: first seen %s, want %s",
+				record.Hash,
+				record.FirstSeen,
+				first.Time,
+			)
+		}
+	}
+}
+
+func TestDedupeTxs(t *testing.T) {
+	txs := [][]byte{{1}, {2}, {1}, {3}, {4}}
+	// The last transaction couldn't be hashed
+	hashes := []string{"a", "b", "a", "c"}
+	dedupedTxs, dedupedHashes := dedupeTxs(txs, hashes)
+	wantTxs := [][]byte{{1}, {2}, {3}, {4}}
+	if fmt.Sprint(dedupedTxs) != fmt.Sprint(wantTxs) {
+		t.Errorf("got txs %v, want %v", dedupedTxs, wantTxs)
+	}
+	if fmt.Sprint(dedupedHashes) != "[a b c]" {
+		t.Errorf("got hashes %v", dedupedHashes)
+	}
+}
+
+func TestSnapshotDrainedExcludesDuplicates(t *testing.T) {
+	withSnapshotState(t, 0)
+	txs := loadDemoTxs(t)
+	unique, _ := dedupeTxs(txs, must(rawTxHashes(txs)))
+	doubled := append(append([][]byte{}, txs...), txs...)
+	snapshot := NewSnapshot(
+		MempoolSizes{NumberOfTxs: uint32(len(unique))},
+		nil,
+		doubled,
+		nil,
+	)
+	if snapshot.Drained != len(unique) ||
+		len(snapshot.Records) != len(unique) {
+		t.Errorf(
+			"got %d drained and %d records, want %d",
+			snapshot.Drained,
+			len(snapshot.Records),
+			len(unique),
+		)
+	}
+}
+
+func must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func TestSnapshotHashIgnoresOrder(t *testing.T) {
+	a := []TxRecord{{Hash: "a", Size: 1}, {Hash: "b", Size: 2}}
+	b := []TxRecord{{Hash: "b", Size: 2}, {Hash: "a", Size: 1}}
+	if snapshotHash(a) != snapshotHash(b) {
+		t.Error("hash depends on order")
+	}
+	c := []TxRecord{{Hash: "a", Size: 1}, {Hash: "b", Size: 3}}
+	if snapshotHash(a) == snapshotHash(c) {
+		t.Error("hash ignores sizes")
+	}
+}
+
+func TestSnapshotHealthy(t *testing.T) {
+	failed := errors.New("failed")
+	tests := []struct {
+		name     string
+		snapshot Snapshot
+		want     bool
+	}{
+		{"ok", Snapshot{}, true},
+		{"sizes failed", Snapshot{SizesErr: failed}, false},
+		{"transactions failed", Snapshot{TxErr: failed}, false},
+	}
+	for _, test := range tests {
+		if got := test.snapshot.Healthy(); got != test.want {
+			t.Errorf("%s: got %t, want %t", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Valid values for SortBy, in the order the sort key cycles through them
+var sortByValues = []string{"size", "time", "label"}
+
+var sortMutex sync.Mutex
+var currentSortBy = "size"
+
+func getSortBy() string {
+	sortMutex.Lock()
+	defer sortMutex.Unlock()
+	return currentSortBy
+}
+
+func setSortBy(sortBy string) error {
+	if !isValidSortBy(sortBy) {
+		return fmt.Errorf("unknown sort: %s", sortBy)
+	}
+	sortMutex.Lock()
+	defer sortMutex.Unlock()
+	currentSortBy = sortBy
+	return nil
+}
+
+// Advances to the next sort and returns it
+func toggleSortBy() string {
+	sortMutex.Lock()
+	defer sortMutex.Unlock()
+	for i, sortBy := range sortByValues {
+		if sortBy == currentSortBy {
+			currentSortBy = sortByValues[(i+1)%len(sortByValues)]
+			return currentSortBy
+		}
+	}
+	currentSortBy = sortByValues[0]
+	return currentSortBy
+}
+
+func isValidSortBy(sortBy string) bool {
+	return slices.Contains(sortByValues, sortBy)
+}
+
+// Valid values for TimeOrder
+var timeOrderValues = []string{"newest", "oldest"}
+
+// Guarded by sortMutex
+var currentTimeOrder = "newest"
+
+func getTimeOrder() string {
+	sortMutex.Lock()
+	defer sortMutex.Unlock()
+	return currentTimeOrder
+}
+
+func setTimeOrder(timeOrder string) error {
+	if !slices.Contains(timeOrderValues, timeOrder) {
+		return fmt.Errorf("unknown time order: %s", timeOrder)
+	}
+	sortMutex.Lock()
+	defer sortMutex.Unlock()
+	currentTimeOrder = timeOrder
+	return nil
+}
+
+// Switches the time sort between newest and oldest first and returns the
+// new order
+func toggleTimeOrder() string {
+	sortMutex.Lock()
+	defer sortMutex.Unlock()
+	if currentTimeOrder == "oldest" {
+		currentTimeOrder = "newest"
+	} else {
+		currentTimeOrder = "oldest"
+	}
+	return currentTimeOrder
+}
+
+// Returns a copy of records ordered for display. Sorting by time uses when
+// each transaction was first seen, falling back to the order the node
+// returned them in, which is the order they entered its mempool. Sorting
+// by label groups transactions by their label, resolved through entries,
+// with the largest first within each label
+func sortTransactions(
+	records []TxRecord,
+	sortBy string,
+	timeOrder string,
+	entries []legendEntry,
+) []TxRecord {
+	sorted := make([]TxRecord, len(records))
+	copy(sorted, records)
+	switch sortBy {
+	case "size":
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Size > sorted[j].Size
+		})
+	case "time":
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].FirstSeen.Before(sorted[j].FirstSeen)
+		})
+		if timeOrder != "oldest" {
+			for i, j := 0, len(sorted)-1; i < j; i, j = i+1, j-1 {
+				sorted[i], sorted[j] = sorted[j], sorted[i]
+			}
+		}
+	case "label":
+		labels := make(map[string]string, len(sorted))
+		for _, record := range sorted {
+			labels[record.Hash] = resolvedLabel(entries, record)
+		}
+		sort.SliceStable(sorted, func(i, j int) bool {
+			cmp := compareLabels(
+				labels[sorted[i].Hash],
+				labels[sorted[j].Hash],
+			)
+			if cmp != 0 {
+				return cmp < 0
+			}
+			return sorted[i].Size > sorted[j].Size
+		})
+	}
+	return sorted
+}
+
+// Orders labels alphabetically, ignoring case, with unlabeled transactions,
+// given as an empty label, last
+func compareLabels(a string, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	cmp := strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	if cmp != 0 {
+		return cmp
+	}
+	return strings.Compare(a, b)
+}
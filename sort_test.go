@@ -0,0 +1,139 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSortTransactions(t *testing.T) {
+	start := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	records := []TxRecord{
+		{Hash: "a", Size: 2, FirstSeen: start.Add(time.Second)},
+		{Hash: "b", Size: 3, FirstSeen: start},
+		{Hash: "c", Size: 1, FirstSeen: start.Add(2 * time.Second)},
+	}
+	tests := []struct {
+		sortBy    string
+		timeOrder string
+		want      string
+	}{
+		{"size", "newest", "bac"},
+		{"time", "newest", "cab"},
+		{"time", "oldest", "bac"},
+	}
+	for _, test := range tests {
+		var hashes strings.Builder
+		for _, record := range sortTransactions(
+			records,
+			test.sortBy,
+			test.timeOrder,
+			nil,
+		) {
+			hashes.WriteString(record.Hash)
+		}
+		if hashes.String() != test.want {
+			t.Errorf(
+				"%s/%s: got %q, want %q",
+				test.sortBy,
+				test.timeOrder,
+				hashes.String(),
+				test.want,
+			)
+		}
+	}
+	if records[0].Hash != "a" {
+		t.Error("sorting modified the input")
+	}
+}
+
+func TestToggleSortBy(t *testing.T) {
+	defer func(saved string) { _ = setSortBy(saved) }(getSortBy())
+	if err := setSortBy("size"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"time", "label", "size"} {
+		if got := toggleSortBy(); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+	if err := setSortBy("bogus"); err == nil {
+		t.Error("expected an error for an unknown sort")
+	}
+}
+
+func TestToggleTimeOrder(t *testing.T) {
+	defer func(saved string) { _ = setTimeOrder(saved) }(getTimeOrder())
+	if err := setTimeOrder("newest"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"oldest", "newest"} {
+		if got := toggleTimeOrder(); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+	if err := setTimeOrder("sideways"); err == nil {
+		t.Error("expected an error for an unknown time order")
+	}
+}
+
+func TestSortTransactionsByLabel(t *testing.T) {
+	entries := []legendEntry{
+		{Icon: "🐱", Name: "Minswap"},
+		{Icon: "🍨", Name: "Sundae"},
+	}
+	records := []TxRecord{
+		{Hash: "a", Size: 1},
+		{Hash: "b", Size: 1, Icon: "🍨"},
+		{Hash: "c", Size: 1, Icon: "🐱"},
+		{Hash: "d", Size: 2, Label: "Governance"},
+		{Hash: "e", Size: 3, Icon: "🐱"},
+	}
+	var hashes strings.Builder
+	for _, record := range sortTransactions(records, "label", "", entries) {
+		hashes.WriteString(record.Hash)
+	}
+	if got := hashes.String(); got != "decba" {
+		t.Errorf("got %q, want %q", got, "decba")
+	}
+}
+
+func TestCompareLabels(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{"Minswap", "Sundae", -1},
+		{"sundae", "Minswap", 1},
+		{"Minswap", "Minswap", 0},
+		{"", "Minswap", 1},
+		{"Minswap", "", -1},
+		{"", "", 0},
+	}
+	for _, test := range tests {
+		if got := compareLabels(test.a, test.b); got != test.want {
+			t.Errorf(
+				"%q vs %q: got %d, want %d",
+				test.a,
+				test.b,
+				got,
+				test.want,
+			)
+		}
+	}
+}
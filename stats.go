@@ -0,0 +1,118 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Count and total size of the transactions seen for a label
+type LabelStats struct {
+	Count int `json:"count"`
+	Bytes int `json:"bytes"`
+}
+
+type statsEntry struct {
+	Time  time.Time
+	Label string
+	Bytes int
+}
+
+// Accumulates per-label counts and sizes over a rolling time window
+type WindowedStats struct {
+	sync.Mutex
+	window  time.Duration
+	entries []statsEntry
+}
+
+var labelStats = NewWindowedStats(0)
+
+func NewWindowedStats(window time.Duration) *WindowedStats {
+	return &WindowedStats{window: window}
+}
+
+// Records a transaction of size bytes seen at now
+func (w *WindowedStats) Add(now time.Time, label string, bytes int) {
+	if w.window <= 0 {
+		return
+	}
+	w.Lock()
+	defer w.Unlock()
+	w.entries = append(
+		w.entries,
+		statsEntry{Time: now, Label: label, Bytes: bytes},
+	)
+	w.expire(now)
+}
+
+// Drops entries older than the window. Entries exactly a window old are
+// kept
+func (w *WindowedStats) expire(now time.Time) {
+	keep := 0
+	for keep < len(w.entries) && now.Sub(w.entries[keep].Time) > w.window {
+		keep++
+	}
+	w.entries = w.entries[keep:]
+}
+
+// Returns the totals per label within the window ending at now
+func (w *WindowedStats) Totals(now time.Time) map[string]LabelStats {
+	w.Lock()
+	defer w.Unlock()
+	w.expire(now)
+	totals := make(map[string]LabelStats)
+	for _, entry := range w.entries {
+		stats := totals[entry.Label]
+		stats.Count++
+		stats.Bytes += entry.Bytes
+		totals[entry.Label] = stats
+	}
+	return totals
+}
+
+// Names a transaction for the stats by its legend entry, falling back to
+// its label. Unlabeled transactions are grouped together
+func statsLabel(entries []legendEntry, record TxRecord) string {
+	icon := strings.TrimSpace(record.Icon)
+	for _, entry := range entries {
+		if entry.Icon == icon && icon != "" {
+			return entry.Name
+		}
+	}
+	if record.Label != "" {
+		return record.Label
+	}
+	if icon != "" {
+		return icon
+	}
+	return "unlabeled"
+}
+
+// Adds the transactions first seen in snapshot to the stats
+func recordLabelStats(cfg *Config, snapshot Snapshot) {
+	entries := allLegendEntries(cfg)
+	for _, record := range snapshot.Records {
+		if !record.FirstSeen.Equal(snapshot.Time) {
+			continue
+		}
+		labelStats.Add(
+			snapshot.Time,
+			statsLabel(entries, record),
+			record.Size,
+		)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowedStats(t *testing.T) {
+	start := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	w := NewWindowedStats(time.Minute)
+	w.Add(start, "Minswap", 100)
+	w.Add(start.Add(30*time.Second), "Minswap", 200)
+	w.Add(start.Add(30*time.Second), "unlabeled", 50)
+	totals := w.Totals(start.Add(time.Minute))
+	if totals["Minswap"] != (LabelStats{Count: 2, Bytes: 300}) {
+		t.Errorf("got %+v within the window", totals["Minswap"])
+	}
+	totals = w.Totals(start.Add(time.Minute + time.Second))
+	if totals["Minswap"] != (LabelStats{Count: 1, Bytes: 200}) {
+		t.Errorf("got %+v after expiry", totals["Minswap"])
+	}
+	if totals["unlabeled"] != (LabelStats{Count: 1, Bytes: 50}) {
+		t.Errorf("got %+v for unlabeled", totals["unlabeled"])
+	}
+	disabled := NewWindowedStats(0)
+	disabled.Add(start, "Minswap", 100)
+	if len(disabled.Totals(start)) != 0 {
+		t.Error("disabled stats recorded an entry")
+	}
+}
+
+func TestStatsLabel(t *testing.T) {
+	tests := []struct {
+		record TxRecord
+		want   string
+	}{
+		{TxRecord{Icon: "🐱"}, "Minswap"},
+		{TxRecord{Icon: "👁️ "}, "Indigo"},
+		{TxRecord{Icon: "🥩", Label: "Stake Delegation"}, "Delegation"},
+		{TxRecord{Icon: "🧪"}, "🧪"},
+		{TxRecord{}, "unlabeled"},
+	}
+	entries := allLegendEntries(&Config{})
+	for _, test := range tests {
+		if got := statsLabel(entries, test.record); got != test.want {
+			t.Errorf("%+v: got %q, want %q", test.record, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strings"
+)
+
+// Prefix of every metric sent to StatsD
+const statsdPrefix = "txtop."
+
+// Sends metrics for each refresh to a StatsD server over UDP
+type StatsdClient struct {
+	conn net.Conn
+}
+
+// Set up in main when STATSD_ADDR is configured
+var statsdClient *StatsdClient
+
+// Creates a client sending to addr, such as localhost:8125. UDP has no
+// handshake, so this only fails for an invalid address
+func NewStatsdClient(addr string) (*StatsdClient, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failure setting up StatsD client: %w", err)
+	}
+	return &StatsdClient{conn: conn}, nil
+}
+
+// Sends lines as a single packet
+func (c *StatsdClient) Send(lines []string) error {
+	_, err := c.conn.Write([]byte(strings.Join(lines, "\n")))
+	return err
+}
+
+// Serializes the metrics for a snapshot in the StatsD line format. Sizes
+// are left out when they couldn't be read
+func statsdLines(snapshot Snapshot) []string {
+	var lines []string
+	gauge := func(name string, value int64) {
+		lines = append(
+			lines,
+			fmt.Sprintf("%s%s:%d|g", statsdPrefix, name, value),
+		)
+	}
+	counter := func(name string, value int64) {
+		lines = append(
+			lines,
+			fmt.Sprintf("%s%s:%d|c", statsdPrefix, name, value),
+		)
+	}
+	if snapshot.SizesErr == nil {
+		gauge("mempool.size", int64(snapshot.Sizes.Size))
+		gauge("mempool.capacity", int64(snapshot.Sizes.Capacity))
+	}
+	gauge("mempool.transactions", int64(snapshot.transactionCount()))
+	gauge("mempool.conflicts", int64(snapshot.Conflicts))
+	counter("refreshes", 1)
+	if !snapshot.Healthy() {
+		counter("errors", 1)
+	}
+	return lines
+}
+
+// Sends the metrics for a snapshot to StatsD, if configured
+func sendStatsd(snapshot Snapshot) {
+	if statsdClient == nil {
+		return
+	}
+	if err := statsdClient.Send(statsdLines(snapshot)); err != nil {
+		log.Printf("failed to send StatsD metrics: %s", err)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestStatsdLines(t *testing.T) {
+	snapshot := Snapshot{
+		Sizes:     MempoolSizes{Capacity: 1000, Size: 250, NumberOfTxs: 3},
+		Drained:   3,
+		Conflicts: 2,
+	}
+	want := []string{
+		"txtop.mempool.size:250|g",
+		"txtop.mempool.capacity:1000|g",
+		"txtop.mempool.transactions:3|g",
+		"txtop.mempool.conflicts:2|g",
+		"txtop.refreshes:1|c",
+	}
+	if got := statsdLines(snapshot); !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestStatsdLinesSizesError(t *testing.T) {
+	snapshot := Snapshot{SizesErr: errors.New("GetSizes failed"), Drained: 1}
+	want := []string{
+		"txtop.mempool.transactions:1|g",
+		"txtop.mempool.conflicts:0|g",
+		"txtop.refreshes:1|c",
+		"txtop.errors:1|c",
+	}
+	if got := statsdLines(snapshot); !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
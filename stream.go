@@ -0,0 +1,208 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Snapshots queued for each stream client before older ones are dropped.
+// Clients diff against the last snapshot they saw, so a dropped snapshot
+// only merges two refreshes into one event
+const streamBuffer = 1
+
+// Fans out each refresh's snapshot to the /mempool/stream clients
+type SnapshotBroker struct {
+	sync.Mutex
+	subscribers map[chan Snapshot]struct{}
+}
+
+// Receives every snapshot read by the refresh loop
+var snapshotStream = NewSnapshotBroker()
+
+func NewSnapshotBroker() *SnapshotBroker {
+	return &SnapshotBroker{
+		subscribers: make(map[chan Snapshot]struct{}),
+	}
+}
+
+// Returns a channel receiving published snapshots, and a function to stop
+// receiving them
+func (b *SnapshotBroker) Subscribe() (<-chan Snapshot, func()) {
+	ch := make(chan Snapshot, streamBuffer)
+	b.Lock()
+	defer b.Unlock()
+	b.subscribers[ch] = struct{}{}
+	return ch, func() {
+		b.Lock()
+		defer b.Unlock()
+		delete(b.subscribers, ch)
+	}
+}
+
+// Sends snapshot to every subscriber without waiting on slow ones, which
+// miss it if they haven't taken the last one yet
+func (b *SnapshotBroker) Publish(snapshot Snapshot) {
+	b.Lock()
+	defer b.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- snapshot:
+		default:
+		}
+	}
+}
+
+// Returns the records in current which aren't in previous, in current's
+// order, and the records in previous which aren't in current, by hash
+func diffRecords(
+	previous []TxRecord,
+	current []TxRecord,
+) ([]TxRecord, []TxRecord) {
+	before := make(map[string]TxRecord, len(previous))
+	for _, record := range previous {
+		before[record.Hash] = record
+	}
+	var added []TxRecord
+	for _, record := range current {
+		if _, ok := before[record.Hash]; ok {
+			delete(before, record.Hash)
+			continue
+		}
+		added = append(added, record)
+	}
+	removed := make([]TxRecord, 0, len(before))
+	for _, record := range before {
+		removed = append(removed, record)
+	}
+	// Map order is random, so sort for stable output
+	sort.Slice(removed, func(i, j int) bool {
+		return removed[i].Hash < removed[j].Hash
+	})
+	return added, removed
+}
+
+// The change between two snapshots sent as a diff event
+type apiMempoolDiff struct {
+	Time        time.Time     `json:"time"`
+	Hash        string        `json:"hash"`
+	Capacity    uint32        `json:"capacity"`
+	Size        uint32        `json:"size"`
+	NumberOfTxs uint32        `json:"numberOfTxs"`
+	Added       []apiTxRecord `json:"added"`
+	Removed     []string      `json:"removed"`
+}
+
+func newAPIMempoolDiff(
+	snapshot Snapshot,
+	added []TxRecord,
+	removed []TxRecord,
+) apiMempoolDiff {
+	hashes := make([]string, 0, len(removed))
+	for _, record := range removed {
+		hashes = append(hashes, record.Hash)
+	}
+	return apiMempoolDiff{
+		Time:        snapshot.Time,
+		Hash:        snapshot.Hash,
+		Capacity:    snapshot.Sizes.Capacity,
+		Size:        snapshot.Sizes.Size,
+		NumberOfTxs: snapshot.Sizes.NumberOfTxs,
+		Added:       newAPITxRecords(added),
+		Removed:     hashes,
+	}
+}
+
+// Writes a Server-Sent Event with data as JSON
+func writeEvent(w http.ResponseWriter, event string, data any) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, body)
+	return err
+}
+
+// Streams the transactions added to and removed from the mempool as
+// Server-Sent Events, one diff event per refresh which changed them. The
+// first event adds every transaction already in the mempool
+func streamHandler(
+	cfg *Config,
+	getSnapshot func() Snapshot,
+	broker *SnapshotBroker,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(
+				w,
+				"streaming not supported",
+				http.StatusInternalServerError,
+			)
+			return
+		}
+		// Subscribe first so no refresh is missed after the first event
+		snapshots, unsubscribe := broker.Subscribe()
+		defer unsubscribe()
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		var previous []TxRecord
+		send := func(snapshot Snapshot) bool {
+			records := snapshot.Records
+			if cfg.App.RedactHashes {
+				records = redactRecords(records, redactKey)
+			}
+			added, removed := diffRecords(previous, records)
+			previous = records
+			if len(added) == 0 && len(removed) == 0 {
+				return true
+			}
+			err := writeEvent(
+				w,
+				"diff",
+				newAPIMempoolDiff(snapshot, added, removed),
+			)
+			if err != nil {
+				log.Printf("failed to write /mempool/stream event: %s", err)
+				return false
+			}
+			flusher.Flush()
+			return true
+		}
+		if snapshot := getSnapshot(); !snapshot.Time.IsZero() {
+			if !send(snapshot) {
+				return
+			}
+		}
+		flusher.Flush()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case snapshot := <-snapshots:
+				if !send(snapshot) {
+					return
+				}
+			}
+		}
+	}
+}
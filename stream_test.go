@@ -0,0 +1,133 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiffRecords(t *testing.T) {
+	previous := []TxRecord{{Hash: "a"}, {Hash: "c"}, {Hash: "b"}}
+	current := []TxRecord{{Hash: "d"}, {Hash: "a"}, {Hash: "e"}}
+	added, removed := diffRecords(previous, current)
+	hashes := func(records []TxRecord) string {
+		var sb strings.Builder
+		for _, record := range records {
+			sb.WriteString(record.Hash)
+		}
+		return sb.String()
+	}
+	if got := hashes(added); got != "de" {
+		t.Errorf("got added %q, want \"de\"", got)
+	}
+	if got := hashes(removed); got != "bc" {
+		t.Errorf("got removed %q, want \"bc\"", got)
+	}
+	added, removed = diffRecords(current, current)
+	if len(added) != 0 || len(removed) != 0 {
+		t.Errorf("got changes between identical records")
+	}
+}
+
+func TestSnapshotBrokerDropsForSlowSubscribers(t *testing.T) {
+	broker := NewSnapshotBroker()
+	snapshots, unsubscribe := broker.Subscribe()
+	broker.Publish(Snapshot{Hash: "first"})
+	// Doesn't block on the subscriber which hasn't taken the first yet
+	broker.Publish(Snapshot{Hash: "second"})
+	if got := (<-snapshots).Hash; got != "first" {
+		t.Errorf("got %q, want first", got)
+	}
+	unsubscribe()
+	broker.Publish(Snapshot{Hash: "third"})
+	select {
+	case snapshot := <-snapshots:
+		t.Errorf("got %q after unsubscribing", snapshot.Hash)
+	default:
+	}
+}
+
+// Reads one event from a stream, returning its name and data
+func readEvent(t *testing.T, reader *bufio.Reader) (string, string) {
+	t.Helper()
+	var event, data string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading event: %s", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			return event, data
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		default:
+			t.Fatalf("unexpected line %q", line)
+		}
+	}
+}
+
+func TestStreamHandler(t *testing.T) {
+	broker := NewSnapshotBroker()
+	initial := testAPISnapshot()
+	server := httptest.NewServer(
+		streamHandler(
+			&Config{},
+			func() Snapshot { return initial },
+			broker,
+		),
+	)
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("got content type %q", got)
+	}
+	reader := bufio.NewReader(resp.Body)
+	event, data := readEvent(t, reader)
+	var diff apiMempoolDiff
+	if err := json.Unmarshal([]byte(data), &diff); err != nil {
+		t.Fatalf("decoding %q: %s", data, err)
+	}
+	if event != "diff" || len(diff.Added) != 1 || diff.Added[0].Hash != "abc" {
+		t.Errorf("got initial %s event %+v", event, diff)
+	}
+	changed := testAPISnapshot()
+	changed.Time = changed.Time.Add(time.Second)
+	changed.Records = []TxRecord{{Hash: "def", Size: 400}}
+	broker.Publish(changed)
+	event, data = readEvent(t, reader)
+	diff = apiMempoolDiff{}
+	if err := json.Unmarshal([]byte(data), &diff); err != nil {
+		t.Fatalf("decoding %q: %s", data, err)
+	}
+	if event != "diff" || len(diff.Added) != 1 ||
+		diff.Added[0].Hash != "def" ||
+		len(diff.Removed) != 1 || diff.Removed[0] != "abc" {
+		t.Errorf("got %s event %+v", event, diff)
+	}
+}
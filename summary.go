@@ -0,0 +1,79 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Failed connections and connection errors reported by the node over the run
+var connectionErrors atomic.Uint64
+
+// Totals for a run, printed on exit
+type RunStats struct {
+	Runtime          time.Duration
+	Refreshes        uint64
+	Peaks            MempoolPeaks
+	UniqueTxs        int
+	ConnectionErrors uint64
+}
+
+// Gathers the totals for the run so far
+func currentRunStats() RunStats {
+	return RunStats{
+		Runtime:          time.Since(startTime),
+		Refreshes:        refreshCount.Load(),
+		Peaks:            getMempoolPeaks(),
+		UniqueTxs:        txAges.Seen(),
+		ConnectionErrors: connectionErrors.Load(),
+	}
+}
+
+// Formats the totals for a run as plain text, for the terminal after the
+// UI exits
+func runSummary(stats RunStats) string {
+	var sb strings.Builder
+	sb.WriteString("txtop run summary\n")
+	sb.WriteString(
+		fmt.Sprintf("  Runtime:             %s\n", formatUptime(stats.Runtime)),
+	)
+	sb.WriteString(fmt.Sprintf("  Refreshes:           %d\n", stats.Refreshes))
+	peakSize := "none"
+	if !stats.Peaks.SizeTime.IsZero() {
+		peakSize = fmt.Sprintf(
+			"%d bytes at %s",
+			stats.Peaks.Size,
+			stats.Peaks.SizeTime.Format(time.TimeOnly),
+		)
+	}
+	sb.WriteString(fmt.Sprintf("  Peak mempool size:   %s\n", peakSize))
+	peakCount := "none"
+	if !stats.Peaks.CountTime.IsZero() {
+		peakCount = fmt.Sprintf(
+			"%d at %s",
+			stats.Peaks.Count,
+			stats.Peaks.CountTime.Format(time.TimeOnly),
+		)
+	}
+	sb.WriteString(fmt.Sprintf("  Peak transactions:   %s\n", peakCount))
+	sb.WriteString(fmt.Sprintf("  Unique transactions: %d\n", stats.UniqueTxs))
+	sb.WriteString(
+		fmt.Sprintf("  Connection errors:   %d\n", stats.ConnectionErrors),
+	)
+	return sb.String()
+}
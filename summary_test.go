@@ -0,0 +1,57 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunSummary(t *testing.T) {
+	peak := time.Date(2024, time.May, 1, 12, 30, 0, 0, time.UTC)
+	got := runSummary(RunStats{
+		Runtime:   90*time.Minute + 5*time.Second,
+		Refreshes: 42,
+		Peaks: MempoolPeaks{
+			Size:      123456,
+			SizeTime:  peak,
+			Count:     17,
+			CountTime: peak.Add(time.Minute),
+		},
+		UniqueTxs:        250,
+		ConnectionErrors: 3,
+	})
+	for _, want := range []string{
+		"Runtime:             01:30:05",
+		"Refreshes:           42",
+		"Peak mempool size:   123456 bytes at 12:30:00",
+		"Peak transactions:   17 at 12:31:00",
+		"Unique transactions: 250",
+		"Connection errors:   3",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("%q not in summary:\n%s", want, got)
+		}
+	}
+}
+
+func TestRunSummaryNoPeaks(t *testing.T) {
+	got := runSummary(RunStats{})
+	if !strings.Contains(got, "Peak mempool size:   none") ||
+		!strings.Contains(got, "Peak transactions:   none") {
+		t.Errorf("missing peaks not shown as none:\n%s", got)
+	}
+}
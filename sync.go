@@ -0,0 +1,119 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blinklabs-io/gouroboros/protocol/common"
+)
+
+// How far the node's tip may trail the wall clock before we consider the
+// node to be syncing
+const syncTolerance = 5 * time.Minute
+
+// A known slot and its start time, after which slots are one second long
+type slotReference struct {
+	Slot uint64
+	Time time.Time
+}
+
+// Start of the Shelley era for networks we know the slot timing of, keyed
+// by network magic
+var networkSlotReferences = map[uint32]slotReference{
+	// mainnet
+	764824073: {
+		Slot: 4492800,
+		Time: time.Date(2020, time.July, 29, 21, 44, 51, 0, time.UTC),
+	},
+	// preprod
+	1: {
+		Slot: 86400,
+		Time: time.Date(2022, time.June, 21, 0, 0, 0, 0, time.UTC),
+	},
+	// preview
+	2: {
+		Slot: 0,
+		Time: time.Date(2022, time.October, 25, 0, 0, 0, 0, time.UTC),
+	},
+}
+
+// Returns the wall clock time at which a slot started
+func slotTime(ref slotReference, slot uint64) time.Time {
+	return ref.Time.Add(
+		time.Duration(int64(slot)-int64(ref.Slot)) * time.Second,
+	)
+}
+
+// Returns how far the tip trails now, and whether that's far enough that
+// the node is likely still syncing
+func nodeSyncLag(
+	ref slotReference,
+	tipSlot uint64,
+	now time.Time,
+) (time.Duration, bool) {
+	lag := now.Sub(slotTime(ref, tipSlot))
+	return lag, lag > syncTolerance
+}
+
+// Returned in place of the tip when nothing needed it
+var errTipNotQueried = errors.New("chain tip not queried")
+
+// Reports whether a refresh should read the chain tip, for the syncing
+// warning on networks we know the slot timing of or for expiry badges
+func needsTip(cfg *Config) bool {
+	_, ok := networkSlotReferences[cfg.Node.NetworkMagic]
+	return ok || cfg.App.ShowExpiry
+}
+
+// Returns a warning line if the node appears to be syncing, or an empty
+// string if it's synced or we can't tell
+func GetSyncWarning(cfg *Config, tipSlot uint64) string {
+	ref, ok := networkSlotReferences[cfg.Node.NetworkMagic]
+	if !ok {
+		return ""
+	}
+	lag, syncing := nodeSyncLag(ref, tipSlot, time.Now())
+	if !syncing {
+		return ""
+	}
+	return fmt.Sprintf(
+		" %sWARNING: node is syncing (tip is %s behind)[white]\n",
+		warningTag(),
+		lag.Truncate(time.Second),
+	)
+}
+
+// The parts of the local state query client used to read the tip
+type tipQuerier interface {
+	GetChainPoint() (*common.Point, error)
+	Release() error
+}
+
+// Returns the slot of the chain tip. The query acquires the ledger state at
+// the tip, which we release again so the node isn't left holding it and the
+// next query, on a reused connection, sees the tip as of then
+func queryTipSlot(client tipQuerier) (uint64, error) {
+	tip, err := client.GetChainPoint()
+	if err != nil {
+		return 0, err
+	}
+	if err := client.Release(); err != nil {
+		return 0, fmt.Errorf("failed to release ledger state: %w", err)
+	}
+	return tip.Slot, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blinklabs-io/gouroboros/protocol/common"
+)
+
+// Mimics the node pinning the ledger state on the first query until it's
+// released
+type fakeTipQuerier struct {
+	tip        uint64
+	acquired   bool
+	pinned     uint64
+	releases   int
+	releaseErr error
+}
+
+func (q *fakeTipQuerier) GetChainPoint() (*common.Point, error) {
+	if !q.acquired {
+		q.acquired = true
+		q.pinned = q.tip
+	}
+	return &common.Point{Slot: q.pinned}, nil
+}
+
+func (q *fakeTipQuerier) Release() error {
+	q.releases++
+	q.acquired = false
+	return q.releaseErr
+}
+
+func TestQueryTipSlotFollowsTip(t *testing.T) {
+	q := &fakeTipQuerier{tip: 100}
+	for _, tip := range []uint64{100, 160, 220} {
+		q.tip = tip
+		slot, err := queryTipSlot(q)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if slot != tip {
+			t.Errorf("got slot %d, want %d", slot, tip)
+		}
+	}
+	if q.acquired {
+		t.Error("ledger state left acquired")
+	}
+	if q.releases != 3 {
+		t.Errorf("got %d releases, want 3", q.releases)
+	}
+}
+
+func TestQueryTipSlotReleaseError(t *testing.T) {
+	q := &fakeTipQuerier{tip: 100, releaseErr: errors.New("closed")}
+	if _, err := queryTipSlot(q); err == nil {
+		t.Error("expected release error")
+	}
+}
+
+func TestNodeSyncLag(t *testing.T) {
+	ref := networkSlotReferences[764824073]
+	tests := []struct {
+		name        string
+		behind      time.Duration
+		wantSyncing bool
+	}{
+		{"at tip", 0, false},
+		{"within tolerance", syncTolerance - time.Second, false},
+		{"beyond tolerance", syncTolerance + time.Second, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tipSlot := ref.Slot + 1000
+			now := slotTime(ref, tipSlot).Add(test.behind)
+			lag, syncing := nodeSyncLag(ref, tipSlot, now)
+			if lag != test.behind || syncing != test.wantSyncing {
+				t.Errorf(
+					"got (%s, %t), want (%s, %t)",
+					lag,
+					syncing,
+					test.behind,
+					test.wantSyncing,
+				)
+			}
+		})
+	}
+}
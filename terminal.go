@@ -0,0 +1,99 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// What we know about the terminal txtop is running in
+type TerminalCaps struct {
+	IsTTY     bool
+	Width     int
+	Height    int
+	Colors    int
+	Term      string
+	ColorTerm string
+}
+
+// Inspects stdout and the environment for terminal capabilities
+func DetectTerminalCaps() TerminalCaps {
+	fd := int(os.Stdout.Fd())
+	isTTY := term.IsTerminal(fd)
+	var width, height int
+	if isTTY {
+		// Size is left at zero when it can't be determined
+		width, height, _ = term.GetSize(fd)
+	}
+	return detectTerminalCaps(isTTY, width, height, os.Getenv)
+}
+
+func detectTerminalCaps(
+	isTTY bool,
+	width int,
+	height int,
+	getenv func(string) string,
+) TerminalCaps {
+	caps := TerminalCaps{
+		IsTTY:     isTTY,
+		Width:     width,
+		Height:    height,
+		Term:      getenv("TERM"),
+		ColorTerm: getenv("COLORTERM"),
+	}
+	caps.Colors = colorDepth(caps.Term, caps.ColorTerm)
+	if !isTTY || getenv("NO_COLOR") != "" {
+		caps.Colors = 0
+	}
+	return caps
+}
+
+// Estimates the number of colors supported from TERM and COLORTERM
+func colorDepth(termName string, colorTerm string) int {
+	colorTerm = strings.ToLower(colorTerm)
+	termName = strings.ToLower(termName)
+	switch {
+	case colorTerm == "truecolor" || colorTerm == "24bit":
+		return 1 << 24
+	case termName == "" || termName == "dumb":
+		return 0
+	case strings.Contains(termName, "256color"):
+		return 256
+	case strings.Contains(termName, "16color"):
+		return 16
+	default:
+		return 8
+	}
+}
+
+func (c TerminalCaps) String() string {
+	tty := "no"
+	if c.IsTTY {
+		tty = "yes"
+	}
+	return fmt.Sprintf(
+		"TTY: %s\nSize: %dx%d\nColors: %d\nTERM: %s\nCOLORTERM: %s\n",
+		tty,
+		c.Width,
+		c.Height,
+		c.Colors,
+		c.Term,
+		c.ColorTerm,
+	)
+}
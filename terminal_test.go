@@ -0,0 +1,76 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "testing"
+
+func TestDetectTerminalCaps(t *testing.T) {
+	tests := []struct {
+		name       string
+		isTTY      bool
+		env        map[string]string
+		wantColors int
+	}{
+		{"no TERM", true, nil, 0},
+		{"dumb", true, map[string]string{"TERM": "dumb"}, 0},
+		{"basic", true, map[string]string{"TERM": "xterm"}, 8},
+		{"256 colors", true, map[string]string{"TERM": "xterm-256color"}, 256},
+		{
+			"truecolor",
+			true,
+			map[string]string{"TERM": "xterm", "COLORTERM": "truecolor"},
+			1 << 24,
+		},
+		{
+			"truecolor on a dumb terminal",
+			true,
+			map[string]string{"TERM": "dumb", "COLORTERM": "truecolor"},
+			1 << 24,
+		},
+		{
+			"NO_COLOR",
+			true,
+			map[string]string{"TERM": "xterm-256color", "NO_COLOR": "1"},
+			0,
+		},
+		{
+			"not a TTY",
+			false,
+			map[string]string{"TERM": "xterm-256color"},
+			0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			getenv := func(key string) string { return test.env[key] }
+			caps := detectTerminalCaps(test.isTTY, 120, 40, getenv)
+			if caps.Colors != test.wantColors {
+				t.Errorf("got %d colors, want %d", caps.Colors, test.wantColors)
+			}
+			if caps.IsTTY != test.isTTY || caps.Width != 120 ||
+				caps.Height != 40 {
+				t.Errorf("got %+v", caps)
+			}
+			if caps.Term != test.env["TERM"] ||
+				caps.ColorTerm != test.env["COLORTERM"] {
+				t.Errorf(
+					"got TERM %q and COLORTERM %q",
+					caps.Term,
+					caps.ColorTerm,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Colors and markers for status cues
+type Theme struct {
+	Error   string
+	Warning string
+	OK      string
+	// Hotkeys in the footer and indicators of active toggles
+	Key string
+	// Prefixes so a status can be told apart without relying on color
+	ErrorMark   string
+	WarningMark string
+	OKMark      string
+}
+
+// Presets selectable with THEME. The color-blind friendly presets avoid
+// telling red and green apart. Errors are a dark orange, warnings a light
+// blue and healthy statuses white, so they also differ in lightness, and
+// each status is marked with a symbol
+var themes = map[string]Theme{
+	"default": {
+		Error:   "red",
+		Warning: "yellow",
+		OK:      "green",
+		Key:     "yellow",
+	},
+	"deuteranopia": {
+		Error:       "#d55e00",
+		Warning:     "#56b4e9",
+		OK:          "white",
+		Key:         "#f0e442",
+		ErrorMark:   "✖ ",
+		WarningMark: "! ",
+		OKMark:      "✔ ",
+	},
+	// Used when THEME isn't set and the terminal shows no colors, so each
+	// status is told apart by its symbol alone
+	"plain": {
+		Error:       "red",
+		Warning:     "yellow",
+		OK:          "green",
+		Key:         "yellow",
+		ErrorMark:   "✖ ",
+		WarningMark: "! ",
+		OKMark:      "✔ ",
+	},
+	// Reds look darker with protanopia, so errors use a lighter orange
+	"protanopia": {
+		Error:       "#e69f00",
+		Warning:     "#56b4e9",
+		OK:          "white",
+		Key:         "#f0e442",
+		ErrorMark:   "✖ ",
+		WarningMark: "! ",
+		OKMark:      "✔ ",
+	},
+}
+
+// Set from THEME in main
+var activeTheme = themes["default"]
+
+// Opening tag for an error message
+func errorTag() string {
+	return "[" + activeTheme.Error + "]" + activeTheme.ErrorMark
+}
+
+// Opening tag for a warning message
+func warningTag() string {
+	return "[" + activeTheme.Warning + "]" + activeTheme.WarningMark
+}
+
+// Opening tag for a healthy status
+func okTag() string {
+	return "[" + activeTheme.OK + "]" + activeTheme.OKMark
+}
+
+// Opening tag for a hotkey or an active toggle
+func keyTag() string {
+	return "[" + activeTheme.Key + "]"
+}
+
+// Opening tag for a row highlighted for attention, such as one spending a
+// watched UTxO
+func highlightTag() string {
+	return "[black:" + activeTheme.Key + "]"
+}
+
+// Picks the theme used when THEME isn't set: plain when the terminal shows
+// no colors, such as with TERM=dumb or NO_COLOR, and default otherwise
+func autoTheme(caps TerminalCaps) string {
+	if caps.Colors == 0 {
+		return "plain"
+	}
+	return "default"
+}
+
+func validateTheme(name string) error {
+	if _, ok := themes[name]; ok {
+		return nil
+	}
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf(
+		"invalid THEME: %q (expected one of: %s)",
+		name,
+		strings.Join(names, ", "),
+	)
+}
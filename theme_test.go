@@ -0,0 +1,93 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+)
+
+func TestThemesDistinguishStatuses(t *testing.T) {
+	for name, theme := range themes {
+		colors := map[string]string{
+			"error":   theme.Error,
+			"warning": theme.Warning,
+			"ok":      theme.OK,
+		}
+		seen := make(map[string]string)
+		for status, color := range colors {
+			if color == "" {
+				t.Errorf("%s: no color for %s", name, status)
+			}
+			if other, ok := seen[color]; ok {
+				t.Errorf("%s: %s and %s share %s", name, status, other, color)
+			}
+			seen[color] = status
+		}
+		// Warnings must stand out from the normal text
+		if theme.Warning == "white" || theme.Error == "white" {
+			t.Errorf("%s: errors or warnings look like normal text", name)
+		}
+		if theme.Key == "" {
+			t.Errorf("%s: no key color", name)
+		}
+	}
+}
+
+func TestThemeTags(t *testing.T) {
+	saved := activeTheme
+	defer func() { activeTheme = saved }()
+	activeTheme = themes["protanopia"]
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{errorTag(), "[#e69f00]✖ "},
+		{warningTag(), "[#56b4e9]! "},
+		{okTag(), "[white]✔ "},
+		{keyTag(), "[#f0e442]"},
+		{highlightTag(), "[black:#f0e442]"},
+	}
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Errorf("got %q, want %q", test.got, test.want)
+		}
+	}
+}
+
+func TestValidateTheme(t *testing.T) {
+	if err := validateTheme("deuteranopia"); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := validateTheme("sepia"); err == nil {
+		t.Error("expected an error for an unknown theme")
+	}
+}
+
+func TestAutoTheme(t *testing.T) {
+	tests := []struct {
+		colors int
+		want   string
+	}{
+		{0, "plain"},
+		{8, "default"},
+		{1 << 24, "default"},
+	}
+	for _, test := range tests {
+		got := autoTheme(TerminalCaps{Colors: test.colors})
+		if got != test.want {
+			t.Errorf("%d colors: got %q, want %q", test.colors, got, test.want)
+		}
+	}
+}
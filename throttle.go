@@ -0,0 +1,94 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// Limits how often queued view updates are drawn. Updates requested within
+// a frame are coalesced, keeping only the latest update for each key
+type RedrawThrottle struct {
+	sync.Mutex
+	interval  time.Duration
+	queue     func(func())
+	afterFunc func(time.Duration, func())
+	now       func() time.Time
+	pending   map[string]func()
+	order     []string
+	scheduled bool
+	last      time.Time
+}
+
+// Creates a throttle drawing at most maxFPS times per second through queue,
+// such as app.QueueUpdateDraw. A maxFPS of zero disables throttling
+func NewRedrawThrottle(maxFPS uint32, queue func(func())) *RedrawThrottle {
+	var interval time.Duration
+	if maxFPS > 0 {
+		interval = time.Second / time.Duration(maxFPS)
+	}
+	return &RedrawThrottle{
+		interval: interval,
+		queue:    queue,
+		afterFunc: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+		now:     time.Now,
+		pending: make(map[string]func()),
+	}
+}
+
+// Requests an update, replacing any pending update with the same key
+func (t *RedrawThrottle) Request(key string, update func()) {
+	if t.interval == 0 {
+		t.queue(update)
+		return
+	}
+	t.Lock()
+	defer t.Unlock()
+	if _, ok := t.pending[key]; !ok {
+		t.order = append(t.order, key)
+	}
+	t.pending[key] = update
+	if t.scheduled {
+		return
+	}
+	t.scheduled = true
+	wait := t.interval - t.now().Sub(t.last)
+	if wait < 0 {
+		wait = 0
+	}
+	t.afterFunc(wait, t.flush)
+}
+
+// Queues all pending updates as a single draw
+func (t *RedrawThrottle) flush() {
+	t.Lock()
+	updates := make([]func(), 0, len(t.order))
+	for _, key := range t.order {
+		updates = append(updates, t.pending[key])
+	}
+	t.pending = make(map[string]func())
+	t.order = nil
+	t.scheduled = false
+	t.last = t.now()
+	t.Unlock()
+	t.queue(func() {
+		for _, update := range updates {
+			update()
+		}
+	})
+}
@@ -0,0 +1,112 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRedrawThrottleCoalesces(t *testing.T) {
+	var queued []func()
+	throttle := NewRedrawThrottle(10, func(update func()) {
+		queued = append(queued, update)
+	})
+	var scheduled []func()
+	throttle.afterFunc = func(_ time.Duration, f func()) {
+		scheduled = append(scheduled, f)
+	}
+	var ran []string
+	throttle.Request("content", func() { ran = append(ran, "content 1") })
+	throttle.Request("footer", func() { ran = append(ran, "footer") })
+	throttle.Request("content", func() { ran = append(ran, "content 2") })
+	if len(scheduled) != 1 {
+		t.Fatalf("got %d flushes scheduled, want 1", len(scheduled))
+	}
+	scheduled[0]()
+	if len(queued) != 1 {
+		t.Fatalf("got %d draws queued, want 1", len(queued))
+	}
+	queued[0]()
+	want := []string{"content 2", "footer"}
+	if fmt.Sprint(ran) != fmt.Sprint(want) {
+		t.Errorf("got %v, want %v", ran, want)
+	}
+}
+
+func TestRedrawThrottleDisabled(t *testing.T) {
+	var queued int
+	throttle := NewRedrawThrottle(0, func(update func()) {
+		queued++
+		update()
+	})
+	for range 3 {
+		throttle.Request("content", func() {})
+	}
+	if queued != 3 {
+		t.Errorf("got %d draws, want 3", queued)
+	}
+}
+
+// Hammers view updates from several goroutines, as the refresh, sizes and
+// error loops do. Run with -race to check updates only touch the views and
+// displayed from the single event loop
+func TestConcurrentViewUpdates(t *testing.T) {
+	events := make(chan func(), 100)
+	loopDone := make(chan struct{})
+	go func() {
+		for update := range events {
+			update()
+		}
+		close(loopDone)
+	}()
+	saved := redraw
+	defer func() { redraw = saved }()
+	for _, maxFPS := range []uint32{0, 1000} {
+		redraw = NewRedrawThrottle(maxFPS, func(update func()) {
+			events <- update
+		})
+		var wg sync.WaitGroup
+		for worker := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range 50 {
+					current := Content{
+						Main:  fmt.Sprintf("worker %d update %d", worker, i),
+						Watch: fmt.Sprintf("watch %d", i),
+					}
+					redraw.Request("content", func() { updateUI(current) })
+					if i%10 == 0 {
+						redraw.Request("content", func() {
+							text.SetText("error")
+							displayed.Main = ""
+						})
+					}
+				}
+			}()
+		}
+		wg.Wait()
+	}
+	// Let any throttled flush land before stopping the loop
+	time.Sleep(10 * time.Millisecond)
+	done := make(chan struct{})
+	events <- func() { close(done) }
+	<-done
+	close(events)
+	<-loopDone
+}
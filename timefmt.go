@@ -0,0 +1,42 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Valid values for TimeFormat
+var timeFormats = []string{"relative", "absolute"}
+
+// Whether times are shown relative to now, toggled at runtime
+var relativeTimes atomic.Bool
+
+// Flips between relative and absolute times, returning whether times are
+// now relative
+func toggleTimeFormat() bool {
+	relative := !relativeTimes.Load()
+	relativeTimes.Store(relative)
+	return relative
+}
+
+// Formats t either as a time of day or as how long before now it was
+func formatTimestamp(t time.Time, now time.Time, relative bool) string {
+	if !relative {
+		return t.Format(time.TimeOnly)
+	}
+	return now.Sub(t).Truncate(time.Second).String() + " ago"
+}
@@ -0,0 +1,37 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatTimestamp(t *testing.T) {
+	now := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	then := now.Add(-90*time.Second - 300*time.Millisecond)
+	tests := []struct {
+		relative bool
+		want     string
+	}{
+		{true, "1m30s ago"},
+		{false, "11:58:29"},
+	}
+	for _, test := range tests {
+		if got := formatTimestamp(then, now, test.relative); got != test.want {
+			t.Errorf("relative %t: got %q, want %q", test.relative, got, test.want)
+		}
+	}
+}
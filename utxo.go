@@ -0,0 +1,63 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/blinklabs-io/gouroboros/ledger"
+)
+
+// Returns the inputs a transaction spends, as txhash#index
+func spentInputs(tx ledger.Transaction) []string {
+	inputs := make([]string, 0, len(tx.Inputs()))
+	for _, input := range tx.Inputs() {
+		inputs = append(
+			inputs,
+			fmt.Sprintf("%s#%d", input.Id().String(), input.Index()),
+		)
+	}
+	return inputs
+}
+
+// Reports whether record spends any of utxos
+func spendsUTxO(record TxRecord, utxos []string) bool {
+	for _, input := range record.Inputs {
+		if slices.Contains(utxos, input) {
+			return true
+		}
+	}
+	return false
+}
+
+// Normalizes WATCH_UTXO entries, which must be txhash#index
+func validateWatchUTxOs(utxos []string) error {
+	for i, utxo := range utxos {
+		utxo = strings.ToLower(strings.TrimSpace(utxo))
+		hash, index, ok := strings.Cut(utxo, "#")
+		if _, err := strconv.ParseUint(index, 10, 32); !ok || err != nil ||
+			len(hash) != 64 {
+			return fmt.Errorf(
+				"invalid WATCH_UTXO entry: %q (expected txhash#index)",
+				utxo,
+			)
+		}
+		utxos[i] = utxo
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateWatchUTxOs(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	utxos := []string{" " + strings.ToUpper(hash) + "#0 "}
+	if err := validateWatchUTxOs(utxos); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if utxos[0] != hash+"#0" {
+		t.Errorf("not normalized: %q", utxos[0])
+	}
+	for _, invalid := range []string{hash, hash + "#", hash + "#x", "ab#1"} {
+		if err := validateWatchUTxOs([]string{invalid}); err == nil {
+			t.Errorf("%q: expected an error", invalid)
+		}
+	}
+}
+
+func TestSpendsUTxO(t *testing.T) {
+	record := TxRecord{Inputs: []string{"aa#0", "bb#1"}}
+	tests := []struct {
+		utxos []string
+		want  bool
+	}{
+		{[]string{"bb#1"}, true},
+		{[]string{"bb#0"}, false},
+		{nil, false},
+	}
+	for _, test := range tests {
+		if got := spendsUTxO(record, test.utxos); got != test.want {
+			t.Errorf("%v: got %t, want %t", test.utxos, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/blinklabs-io/gouroboros/ledger"
+)
+
+// Lovelace in one ADA
+const lovelacePerADA = 1_000_000
+
+// Sums the lovelace paid to every output. Native assets carried by an
+// output aren't counted, only its coin
+func outputValue(tx ledger.Transaction) uint64 {
+	var total uint64
+	for _, output := range tx.Outputs() {
+		total += output.Amount()
+	}
+	return total
+}
+
+// Formats lovelace as ADA with two decimals, rounding to the nearest
+// hundredth
+func formatADA(lovelace uint64) string {
+	hundredths := (lovelace + lovelacePerADA/200) / (lovelacePerADA / 100)
+	return fmt.Sprintf("%d.%02d", hundredths/100, hundredths%100)
+}
+
+var valueColumn = column{
+	header:   "ADA:",
+	width:    14,
+	priority: 3,
+	value: func(record TxRecord) string {
+		return formatADA(record.Value)
+	},
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/blinklabs-io/gouroboros/cbor"
+	lcommon "github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/blinklabs-io/gouroboros/ledger/mary"
+)
+
+func TestOutputValue(t *testing.T) {
+	assets := lcommon.NewMultiAsset(
+		map[lcommon.Blake2b224]map[cbor.ByteString]uint64{
+			lcommon.NewBlake2b224(make([]byte, 28)): {
+				cbor.NewByteString([]byte("token")): 5_000_000_000,
+			},
+		},
+	)
+	tx := fakeTx{
+		outputs: []lcommon.TransactionOutput{
+			fakeOutput{amount: 1_500_000},
+			mary.MaryTransactionOutput{
+				OutputAmount: mary.MaryTransactionOutputValue{
+					Amount: 2_000_000,
+					Assets: &assets,
+				},
+			},
+		},
+	}
+	if got := outputValue(tx); got != 3_500_000 {
+		t.Errorf("got %d lovelace, want 3500000", got)
+	}
+	if got := outputValue(fakeTx{}); got != 0 {
+		t.Errorf("got %d lovelace without outputs", got)
+	}
+}
+
+func TestFormatADA(t *testing.T) {
+	tests := []struct {
+		lovelace uint64
+		want     string
+	}{
+		{0, "0.00"},
+		{1, "0.00"},
+		{5_000, "0.01"},
+		{1_234_567, "1.23"},
+		{1_995_000, "2.00"},
+		{45_000_000_000_000, "45000000.00"},
+	}
+	for _, test := range tests {
+		if got := formatADA(test.lovelace); got != test.want {
+			t.Errorf("%d: got %q, want %q", test.lovelace, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/blinklabs-io/gouroboros/ledger"
+)
+
+// Kinds of known wallets and their default icons
+var walletCategoryIcons = map[string]string{
+	"exchange": "🏦",
+	"bridge":   "🌉",
+}
+
+// A wallet, such as an exchange's hot wallet, listed in WALLETS_FILE.
+// Address is either a payment address or a stake address, which matches
+// every address delegated to it
+type knownWallet struct {
+	Address  string `json:"address"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Icon     string `json:"icon,omitempty"`
+}
+
+// Loaded from WALLETS_FILE in main
+var knownWallets []knownWallet
+
+// Reads a JSON list of wallets from path, filling in the icon for their
+// category where none is given
+func LoadWallets(path string) ([]knownWallet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var wallets []knownWallet
+	if err := json.Unmarshal(data, &wallets); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for i, wallet := range wallets {
+		wallet.Address = strings.TrimSpace(wallet.Address)
+		wallet.Category = strings.ToLower(strings.TrimSpace(wallet.Category))
+		if wallet.Address == "" || wallet.Name == "" {
+			return nil, fmt.Errorf(
+				"wallet %d in %s: address and name are required",
+				i,
+				path,
+			)
+		}
+		icon, ok := walletCategoryIcons[wallet.Category]
+		if !ok {
+			return nil, fmt.Errorf(
+				"wallet %q in %s: invalid category %q (expected one of: %s)",
+				wallet.Name,
+				path,
+				wallet.Category,
+				strings.Join(walletCategories(), ", "),
+			)
+		}
+		if wallet.Icon == "" {
+			wallet.Icon = icon
+		}
+		wallets[i] = wallet
+	}
+	return wallets, nil
+}
+
+// Returns the wallet categories in a stable order
+func walletCategories() []string {
+	categories := make([]string, 0, len(walletCategoryIcons))
+	for category := range walletCategoryIcons {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// Matches output addresses, and the stake addresses they're delegated to,
+// against known wallets, labeling transactions with the wallet's name. The
+// last matching output wins
+func walletClassifier(wallets []knownWallet) Classifier {
+	byAddress := make(map[string]knownWallet, len(wallets))
+	for _, wallet := range wallets {
+		byAddress[wallet.Address] = wallet
+	}
+	return ClassifierFunc(func(tx ledger.Transaction) (string, string, bool) {
+		if len(byAddress) == 0 {
+			return "", "", false
+		}
+		outputs := tx.Outputs()
+		for i := len(outputs) - 1; i >= 0; i-- {
+			address := outputs[i].Address()
+			if wallet, ok := byAddress[address.String()]; ok {
+				return wallet.Name, wallet.Icon, true
+			}
+			stakeAddress := address.StakeAddress()
+			if stakeAddress == nil {
+				continue
+			}
+			if wallet, ok := byAddress[stakeAddress.String()]; ok {
+				return wallet.Name, wallet.Icon, true
+			}
+		}
+		return "", "", false
+	})
+}
+
+// Builds a legend entry per wallet icon. Category icons are named after
+// their category, while custom icons are named after the wallet
+func walletLegendEntries(wallets []knownWallet) []legendEntry {
+	var entries []legendEntry
+	var icons []string
+	for _, wallet := range wallets {
+		if slices.Contains(icons, wallet.Icon) {
+			continue
+		}
+		icons = append(icons, wallet.Icon)
+		name := wallet.Name
+		if wallet.Icon == walletCategoryIcons[wallet.Category] {
+			name = strings.ToUpper(wallet.Category[:1]) + wallet.Category[1:]
+		}
+		entries = append(
+			entries,
+			legendEntry{Icon: wallet.Icon, Name: name, Category: "wallets"},
+		)
+	}
+	return entries
+}
@@ -0,0 +1,144 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	lcommon "github.com/blinklabs-io/gouroboros/ledger/common"
+)
+
+func writeWalletsFile(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "wallets.json")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadWallets(t *testing.T) {
+	path := writeWalletsFile(t, `[
+		{"address": "`+sundaeAddress+`", "name": "Big Exchange", "category": " Exchange "},
+		{"address": "`+sealStake+`", "name": "Some Bridge", "category": "bridge", "icon": "🛶"}
+	]`)
+	wallets, err := LoadWallets(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(wallets) != 2 {
+		t.Fatalf("got %d wallets, want 2", len(wallets))
+	}
+	if wallets[0].Category != "exchange" || wallets[0].Icon != "🏦" {
+		t.Errorf("category icon not filled in: %+v", wallets[0])
+	}
+	if wallets[1].Icon != "🛶" {
+		t.Errorf("custom icon not kept: %+v", wallets[1])
+	}
+}
+
+func TestLoadWalletsInvalid(t *testing.T) {
+	for _, data := range []string{
+		`[{"address": "addr1", "name": "Dex", "category": "dapp"}]`,
+		`[{"address": "", "name": "Nameless", "category": "exchange"}]`,
+		`{"address": "addr1"}`,
+	} {
+		if _, err := LoadWallets(writeWalletsFile(t, data)); err == nil {
+			t.Errorf("%s: expected an error", data)
+		}
+	}
+}
+
+func TestWalletClassifier(t *testing.T) {
+	classifier := walletClassifier([]knownWallet{
+		{Address: sundaeAddress, Name: "Big Exchange", Icon: "🏦"},
+		{Address: sealStake, Name: "Some Bridge", Icon: "🌉"},
+	})
+	tests := []struct {
+		name      string
+		outputs   func(t *testing.T) []lcommon.TransactionOutput
+		wantLabel string
+		wantIcon  string
+	}{
+		{
+			name: "payment address",
+			outputs: func(t *testing.T) []lcommon.TransactionOutput {
+				return []lcommon.TransactionOutput{
+					testOutput(t, sundaeAddress),
+				}
+			},
+			wantLabel: "Big Exchange",
+			wantIcon:  "🏦",
+		},
+		{
+			name: "stake address",
+			outputs: func(t *testing.T) []lcommon.TransactionOutput {
+				return []lcommon.TransactionOutput{
+					testStakeOutput(t, sealStake),
+				}
+			},
+			wantLabel: "Some Bridge",
+			wantIcon:  "🌉",
+		},
+		{
+			name: "unknown",
+			outputs: func(t *testing.T) []lcommon.TransactionOutput {
+				return []lcommon.TransactionOutput{
+					testOutput(t, minswapAddress),
+				}
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			label, icon, ok := classifier.Classify(
+				fakeTx{outputs: test.outputs(t)},
+			)
+			if label != test.wantLabel || icon != test.wantIcon ||
+				ok != (test.wantLabel != "") {
+				t.Errorf(
+					"got (%q, %q, %t), want (%q, %q)",
+					label,
+					icon,
+					ok,
+					test.wantLabel,
+					test.wantIcon,
+				)
+			}
+		})
+	}
+}
+
+func TestWalletLegendEntries(t *testing.T) {
+	entries := walletLegendEntries([]knownWallet{
+		{Name: "A", Category: "exchange", Icon: "🏦"},
+		{Name: "B", Category: "exchange", Icon: "🏦"},
+		{Name: "C", Category: "bridge", Icon: "🛶"},
+	})
+	want := []legendEntry{
+		{Icon: "🏦", Name: "Exchange", Category: "wallets"},
+		{Icon: "🛶", Name: "C", Category: "wallets"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %+v, want %+v", entries, want)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entry %d: got %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+)
+
+// Reports whether a transaction hash starts with any of the watched prefixes
+func isWatched(record TxRecord, watchHashes []string) bool {
+	for _, prefix := range watchHashes {
+		if prefix != "" && strings.HasPrefix(record.Hash, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns whether a record stands out in the transaction list, because it's
+// watched, spends a watched UTxO or is selected
+func highlighter(cfg *Config) func(TxRecord) bool {
+	return func(record TxRecord) bool {
+		return isWatched(record, cfg.App.WatchHashes) ||
+			spendsUTxO(record, cfg.App.WatchUTxOs) ||
+			isSelected(record)
+	}
+}
+
+// Formats the watched transactions in a snapshot
+func RenderWatch(cfg *Config, snapshot Snapshot) string {
+	var watched []TxRecord
+	for _, record := range snapshot.Records {
+		if isWatched(record, cfg.App.WatchHashes) {
+			watched = append(watched, record)
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString(" [white]Watched transactions:\n")
+	if len(cfg.App.WatchHashes) == 0 {
+		sb.WriteString(
+			" " + keyTag() + "set WATCH_HASHES to watch transactions[white]\n",
+		)
+		return sb.String()
+	}
+	sb.WriteString(FormatTransactions(cfg, watched))
+	return sb.String()
+}
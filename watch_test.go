@@ -0,0 +1,56 @@
+// Copyright 2024 Blink Labs Software
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsWatched(t *testing.T) {
+	record := TxRecord{Hash: "abcdef"}
+	tests := []struct {
+		watch []string
+		want  bool
+	}{
+		{[]string{"abc"}, true},
+		{[]string{"abcdef"}, true},
+		{[]string{"bcd", "ab"}, true},
+		{[]string{"def"}, false},
+		{[]string{""}, false},
+		{nil, false},
+	}
+	for _, test := range tests {
+		if got := isWatched(record, test.watch); got != test.want {
+			t.Errorf("%v: got %t, want %t", test.watch, got, test.want)
+		}
+	}
+}
+
+func TestRenderWatch(t *testing.T) {
+	snapshot := Snapshot{
+		Records: []TxRecord{{Hash: "abc123"}, {Hash: "def456"}},
+	}
+	cfg := &Config{}
+	text := RenderWatch(cfg, snapshot)
+	if !strings.Contains(text, "set WATCH_HASHES") {
+		t.Errorf("missing hint: %q", text)
+	}
+	cfg.App.WatchHashes = []string{"abc"}
+	text = RenderWatch(cfg, snapshot)
+	if !strings.Contains(text, "abc123") || strings.Contains(text, "def456") {
+		t.Errorf("got %q", text)
+	}
+}